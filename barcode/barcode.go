@@ -0,0 +1,199 @@
+// Package barcode renders IDs as 1D barcodes, for scanners that don't read QR codes. Two symbologies are supported:
+// Code 128 (code set B, which covers printable ASCII) and Code 39 (digits, uppercase letters and a few symbols).
+// Barcodes can be rendered as PNG or as SVG.
+package barcode
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"strings"
+
+	"github.com/KarelKubat/hrid/er"
+)
+
+// Symbology is a type of barcode.
+type Symbology int
+
+const (
+	Code128 Symbology = iota // Code 128, code set B
+	Code39                   // Code 39, without check character
+)
+
+const (
+	// QuietZone is the number of narrow modules of white space before and after a rendered barcode.
+	QuietZone = 10
+	// code39Wide is the width of a wide element of Code 39, in narrow modules.
+	code39Wide = 3
+)
+
+// Symbologies maps names to symbologies, e.g. for flags.
+var Symbologies = map[string]Symbology{
+	"code128": Code128,
+	"code39":  Code39,
+}
+
+// String returns the name of a symbology.
+func (s Symbology) String() string {
+	return []string{"code128", "code39"}[s]
+}
+
+// code128 holds the widths of the bars and spaces of each Code 128 value, starting with a bar. Values 103 to 105 are
+// the start codes of code sets A, B and C; 106 is the stop code.
+var code128 = [...]string{
+	"212222", "222122", "222221", "121223", "121322", "131222", "122213", "122312", "132212", "221213",
+	"221312", "231212", "112232", "122132", "122231", "113222", "123122", "123221", "223211", "221132",
+	"221231", "213212", "223112", "312131", "311222", "321122", "321221", "312212", "322112", "322211",
+	"212123", "212321", "232121", "111323", "131123", "131321", "112313", "132113", "132311", "211313",
+	"231113", "231311", "112133", "112331", "132131", "113123", "113321", "133121", "313121", "211331",
+	"231131", "213113", "213311", "213131", "311123", "311321", "331121", "312113", "312311", "332111",
+	"314111", "221411", "431111", "111224", "111422", "121124", "121421", "141122", "141221", "112214",
+	"112412", "122114", "122411", "142112", "142211", "241211", "221114", "413111", "241112", "134111",
+	"111242", "121142", "121241", "114212", "124112", "124211", "411212", "421112", "421211", "212141",
+	"214121", "412121", "111143", "111341", "131141", "114113", "114311", "411113", "411311", "113141",
+	"114131", "311141", "411131", "211412", "211214", "211232", "2331112",
+}
+
+const (
+	code128StartB = 104
+	code128Stop   = 106
+)
+
+// code39 holds the narrow (n) and wide (w) bars and spaces of each Code 39 rune, starting with a bar. The asterisk
+// is the start and stop character.
+var code39 = map[rune]string{
+	'0': "nnnwwnwnn", '1': "wnnwnnnnw", '2': "nnwwnnnnw", '3': "wnwwnnnnn", '4': "nnnwwnnnw",
+	'5': "wnnwwnnnn", '6': "nnwwwnnnn", '7': "nnnwnnwnw", '8': "wnnwnnwnn", '9': "nnwwnnwnn",
+	'A': "wnnnnwnnw", 'B': "nnwnnwnnw", 'C': "wnwnnwnnn", 'D': "nnnnwwnnw", 'E': "wnnnwwnnn",
+	'F': "nnwnwwnnn", 'G': "nnnnnwwnw", 'H': "wnnnnwwnn", 'I': "nnwnnwwnn", 'J': "nnnnwwwnn",
+	'K': "wnnnnnnww", 'L': "nnwnnnnww", 'M': "wnwnnnnwn", 'N': "nnnnwnnww", 'O': "wnnnwnnwn",
+	'P': "nnwnwnnwn", 'Q': "nnnnnnwww", 'R': "wnnnnnwwn", 'S': "nnwnnnwwn", 'T': "nnnnwnwwn",
+	'U': "wwnnnnnnw", 'V': "nwwnnnnnw", 'W': "wwwnnnnnn", 'X': "nwnnwnnnw", 'Y': "wwnnwnnnn",
+	'Z': "nwwnwnnnn", '-': "nwnnnnwnw", '.': "wwnnnnwnn", ' ': "nwwnnnwnn", '$': "nwnwnwnnn",
+	'/': "nwnwnnnwn", '+': "nwnnnwnwn", '%': "nnnwnwnwn", '*': "nwnnwnwnn",
+}
+
+// Code is an encoded barcode.
+type Code struct {
+	Symbology Symbology
+	Text      string
+
+	widths []int // Widths of alternating bars and spaces in modules, starting with a bar
+}
+
+// Check returns an error when a rune of the alphabet can't be represented in the symbology.
+func (s Symbology) Check(alphabet string) *er.Err {
+	for _, r := range alphabet {
+		switch s {
+		case Code128:
+			if r < ' ' || r > '~' {
+				return er.Newf(er.UnrepresentableError, "%q can't be represented in %v", r, s)
+			}
+		case Code39:
+			if _, ok := code39[r]; !ok || r == '*' {
+				return er.Newf(er.UnrepresentableError, "%q can't be represented in %v", r, s)
+			}
+		}
+	}
+	return nil
+}
+
+// Encode returns the barcode for a text. An error occurs when the text holds a rune that the symbology lacks.
+func Encode(s Symbology, text string) (*Code, *er.Err) {
+	if err := s.Check(text); err != nil {
+		return nil, err
+	}
+	c := &Code{
+		Symbology: s,
+		Text:      text,
+	}
+	switch s {
+	case Code128:
+		c.add128(code128StartB)
+		sum := code128StartB
+		for i, r := range text {
+			value := int(r - ' ')
+			c.add128(value)
+			sum += (i + 1) * value
+		}
+		c.add128(sum % 103)
+		c.add128(code128Stop)
+	case Code39:
+		for i, r := range "*" + text + "*" {
+			if i > 0 {
+				c.widths = append(c.widths, 1) // Narrow gap between characters
+			}
+			for _, e := range code39[r] {
+				if e == 'w' {
+					c.widths = append(c.widths, code39Wide)
+				} else {
+					c.widths = append(c.widths, 1)
+				}
+			}
+		}
+	}
+	return c, nil
+}
+
+// add128 is a helper that appends the bars and spaces of a Code 128 value.
+func (c *Code) add128(value int) {
+	for _, w := range code128[value] {
+		c.widths = append(c.widths, int(w-'0'))
+	}
+}
+
+// Modules returns the modules of the barcode, true for a bar, without quiet zones.
+func (c *Code) Modules() []bool {
+	out := []bool{}
+	for i, w := range c.widths {
+		for j := 0; j < w; j++ {
+			out = append(out, i%2 == 0)
+		}
+	}
+	return out
+}
+
+// PNG writes the barcode as a PNG image, where each module is scale pixels wide and the bars are height pixels high.
+// Quiet zones are added.
+func (c *Code) PNG(w io.Writer, scale, height int) error {
+	modules := c.Modules()
+	width := (len(modules) + 2*QuietZone) * scale
+	img := image.NewGray(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		m := x/scale - QuietZone
+		shade := color.Gray{Y: 255}
+		if m >= 0 && m < len(modules) && modules[m] {
+			shade = color.Gray{Y: 0}
+		}
+		for y := 0; y < height; y++ {
+			img.SetGray(x, y, shade)
+		}
+	}
+	return png.Encode(w, img)
+}
+
+// SVG returns the barcode as an SVG image, where each module is scale units wide and the bars are height units high.
+// Quiet zones are added.
+func (c *Code) SVG(scale, height int) string {
+	width := 0
+	for _, w := range c.widths {
+		width += w
+	}
+	width = (width + 2*QuietZone) * scale
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n",
+		width, height, width, height)
+	fmt.Fprintf(&b, `<rect width="%d" height="%d" fill="#fff"/>`+"\n", width, height)
+	b.WriteString(`<path fill="#000" d="`)
+	x := QuietZone * scale
+	for i, w := range c.widths {
+		if i%2 == 0 {
+			fmt.Fprintf(&b, "M%d 0h%dv%dh-%dz", x, w*scale, height, w*scale)
+		}
+		x += w * scale
+	}
+	b.WriteString(`"/>` + "\n</svg>\n")
+	return b.String()
+}
@@ -0,0 +1,143 @@
+package barcode
+
+import (
+	"bytes"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/KarelKubat/hrid/er"
+)
+
+func TestTables(t *testing.T) {
+	seen := map[string]bool{}
+	for value, pattern := range code128 {
+		sum := 0
+		for _, w := range pattern {
+			sum += int(w - '0')
+		}
+		want := 11
+		if value == code128Stop {
+			want = 13
+		}
+		if sum != want {
+			t.Errorf("code128[%v] = %q spans %v modules, want %v", value, pattern, sum, want)
+		}
+		if seen[pattern] {
+			t.Errorf("code128[%v] = %q repeats", value, pattern)
+		}
+		seen[pattern] = true
+	}
+
+	seen = map[string]bool{}
+	for r, pattern := range code39 {
+		if len(pattern) != 9 || strings.Count(pattern, "w") != 3 {
+			t.Errorf("code39[%q] = %q, want 9 elements of which 3 wide", r, pattern)
+		}
+		if seen[pattern] {
+			t.Errorf("code39[%q] = %q repeats", r, pattern)
+		}
+		seen[pattern] = true
+	}
+}
+
+func TestEncode(t *testing.T) {
+	for _, test := range []struct {
+		symbology   Symbology
+		text        string
+		wantModules int
+		wantCode    er.Code
+	}{
+		{
+			// Start, 2 characters, checksum: 11 modules each; stop: 13 modules.
+			symbology:   Code128,
+			text:        "AB",
+			wantModules: 4*11 + 13,
+		},
+		{
+			symbology:   Code128,
+			text:        "cnh-74",
+			wantModules: 8*11 + 13,
+		},
+		{
+			symbology: Code128,
+			text:      "tab\t",
+			wantCode:  er.UnrepresentableError,
+		},
+		{
+			// Start, 2 characters, stop: 6 narrow and 3 wide elements each, plus 3 gaps.
+			symbology:   Code39,
+			text:        "A1",
+			wantModules: 4*(6+3*code39Wide) + 3,
+		},
+		{
+			symbology: Code39,
+			text:      "a1",
+			wantCode:  er.UnrepresentableError,
+		},
+		{
+			symbology: Code39,
+			text:      "A*1",
+			wantCode:  er.UnrepresentableError,
+		},
+	} {
+		c, err := Encode(test.symbology, test.text)
+		gotCode := er.None
+		if err != nil {
+			gotCode = err.Code
+		}
+		if gotCode != test.wantCode {
+			t.Errorf("Encode(%v,%q) = _,%v, want error code %v", test.symbology, test.text, err, test.wantCode)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		modules := c.Modules()
+		if len(modules) != test.wantModules {
+			t.Errorf("Encode(%v,%q) has %v modules, want %v", test.symbology, test.text, len(modules), test.wantModules)
+		}
+		if !modules[0] || !modules[len(modules)-1] {
+			t.Errorf("Encode(%v,%q) doesn't start and end with a bar", test.symbology, test.text)
+		}
+	}
+}
+
+func TestCode128Checksum(t *testing.T) {
+	// Start B (104) + 1*W(55) + 2*i(73) + 3*k(75) + 4*i + 5*p(80) + 6*e(69) + 7*d(68) + 8*i + 9*a(65) = 3281,
+	// and 3281 % 103 = 88.
+	c, err := Encode(Code128, "Wikipedia")
+	if err != nil {
+		t.Fatalf("Encode returned unexpected error %v", err)
+	}
+	n := len(c.widths)
+	got := ""
+	for _, w := range c.widths[n-13 : n-7] {
+		got += string(rune('0' + w))
+	}
+	if got != code128[88] {
+		t.Errorf("checksum pattern = %q, want %q (value 88)", got, code128[88])
+	}
+}
+
+func TestRender(t *testing.T) {
+	c, err := Encode(Code39, "CNH74")
+	if err != nil {
+		t.Fatalf("Encode returned unexpected error %v", err)
+	}
+	var buf bytes.Buffer
+	if err := c.PNG(&buf, 2, 50); err != nil {
+		t.Fatalf("PNG returned unexpected error %v", err)
+	}
+	img, err2 := png.Decode(&buf)
+	if err2 != nil {
+		t.Fatalf("png.Decode returned unexpected error %v", err2)
+	}
+	if want := (len(c.Modules()) + 2*QuietZone) * 2; img.Bounds().Dx() != want || img.Bounds().Dy() != 50 {
+		t.Errorf("PNG is %vx%v pixels, want %vx50", img.Bounds().Dx(), img.Bounds().Dy(), want)
+	}
+	svg := c.SVG(2, 50)
+	if !strings.HasPrefix(svg, "<svg ") || strings.Count(svg, "M") != 7*5 {
+		t.Errorf("SVG = %q, want an svg element with 35 bars", svg)
+	}
+}
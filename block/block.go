@@ -0,0 +1,81 @@
+// Package block implements blocklists for generated IDs. An ID is rejected when it contains an unwanted word, or when
+// it contains a run of identical runes that's so long that humans will likely miscount it.
+package block
+
+import (
+	"strings"
+
+	"github.com/KarelKubat/hrid/er"
+)
+
+const (
+	// MaxRun is the default maximum number of identical consecutive runes in an ID.
+	MaxRun = 3
+)
+
+// English holds the default words that may not appear in an ID. Some are spelled using digits, because the default
+// alphabet of package id has digits but lacks some letters (e.g. there's no O, so 0 takes its place).
+var English = []string{
+	"A55", "ANAL", "AN4L", "ARSE", "ASS",
+	"B00B", "BOOB", "BITCH", "BUTT",
+	"C0CK", "COCK", "CRAP", "CUM", "CUNT",
+	"D1CK", "DAMN", "DICK", "DUMB",
+	"FAG", "FART", "FCK", "FUCK", "FUK",
+	"GAY",
+	"HATE", "HELL", "H0M0", "HOMO",
+	"KKK", "KUNT",
+	"N1GG", "N4Z1", "NAZI", "NIGG",
+	"P00", "P0RN", "PEE", "PISS", "POO", "PORN", "PU55Y", "PUSSY",
+	"RAPE", "RAP3",
+	"5EX", "SEX", "SH1T", "SHIT", "SLUT",
+	"T1T", "TIT", "TWAT",
+	"WANK", "WHORE", "WTF",
+}
+
+// List defines what IDs may not contain.
+type List struct {
+	Words  []string // Substrings that may not occur in an ID. Matching ignores casing.
+	MaxRun int      // When non-zero, runs of more than MaxRun identical runes are rejected.
+}
+
+// Default returns a List with the English words and the default MaxRun. The returned List can be freely modified.
+func Default() *List {
+	return &List{
+		Words:  append([]string{}, English...),
+		MaxRun: MaxRun,
+	}
+}
+
+// Check returns an error when the runes contain a blocked word or a too long run. Runs of the pad rune at the start are
+// leading padding, which isn't subject to MaxRun (otherwise all small numbers would be rejected).
+func (l *List) Check(runes []rune, pad rune) *er.Err {
+	upper := strings.ToUpper(string(runes))
+	for _, w := range l.Words {
+		if w == "" {
+			continue
+		}
+		if strings.Contains(upper, strings.ToUpper(w)) {
+			return er.Newf(er.BlockedWordError, "%q contains blocked word %q", string(runes), w)
+		}
+	}
+
+	if l.MaxRun <= 0 {
+		return nil
+	}
+	start := 0
+	for start < len(runes) && runes[start] == pad {
+		start++
+	}
+	run := 0
+	for i := start; i < len(runes); i++ {
+		if i > start && runes[i] == runes[i-1] {
+			run++
+		} else {
+			run = 1
+		}
+		if run > l.MaxRun {
+			return er.Newf(er.RunTooLongError, "%q repeats %v more than %v times", string(runes), string(runes[i]), l.MaxRun)
+		}
+	}
+	return nil
+}
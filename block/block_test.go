@@ -0,0 +1,71 @@
+package block
+
+import (
+	"testing"
+
+	"github.com/KarelKubat/hrid/er"
+)
+
+func TestCheck(t *testing.T) {
+	l := &List{
+		Words:  []string{"BAD", "f00"},
+		MaxRun: 3,
+	}
+	for _, test := range []struct {
+		s        string
+		wantCode er.Code
+	}{
+		{
+			s:        "0001234",
+			wantCode: er.None,
+		},
+		{
+			s:        "0000000000007",
+			wantCode: er.None, // leading padding
+		},
+		{
+			s:        "12BAD34",
+			wantCode: er.BlockedWordError,
+		},
+		{
+			s:        "12bad34",
+			wantCode: er.BlockedWordError,
+		},
+		{
+			s:        "F0012",
+			wantCode: er.BlockedWordError,
+		},
+		{
+			s:        "1222",
+			wantCode: er.None,
+		},
+		{
+			s:        "12222",
+			wantCode: er.RunTooLongError,
+		},
+		{
+			s:        "10000",
+			wantCode: er.RunTooLongError,
+		},
+	} {
+		err := l.Check([]rune(test.s), '0')
+		gotCode := er.None
+		if err != nil {
+			gotCode = err.Code
+		}
+		if gotCode != test.wantCode {
+			t.Errorf("Check(%q) = %v, want %v", test.s, err, test.wantCode)
+		}
+	}
+}
+
+func TestDefault(t *testing.T) {
+	l := Default()
+	l.Words = append(l.Words, "XYZ")
+	if len(Default().Words) != len(English) {
+		t.Errorf("Default() shares its words with an earlier returned list")
+	}
+	if err := l.Check([]rune("00FUCK"), '0'); err == nil || err.Code != er.BlockedWordError {
+		t.Errorf("Check(00FUCK) = %v, want BlockedWordError", err)
+	}
+}
@@ -0,0 +1,111 @@
+// Command hrid-gen generates a converter with compile-time constant options, see package codegen. It is meant for
+// go:generate, e.g.:
+//
+//	//go:generate go run github.com/KarelKubat/hrid/cmd/hrid-gen -config hrid.json -output ids.go
+//
+// which writes ids.go with the converter and ids_test.go with a test that proves its equivalence with package id. With
+// -language typescript or postgresql, a TypeScript module or PL/pgSQL functions are written instead, e.g. ids.ts or
+// ids.sql.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/KarelKubat/flagnames"
+	"github.com/KarelKubat/hrid/codegen"
+	"github.com/KarelKubat/hrid/er"
+	"github.com/KarelKubat/hrid/id"
+)
+
+const (
+	usage = `
+This is hrid-gen, which generates Go code for a converter of human readable IDs.
+Usage:
+  hrid-gen -config FILE.json [FLAGS]
+
+The configuration is the JSON format of hrid -config, see hrid -verbose.
+The language is go (the default), typescript or postgresql.
+The flags can be abbreviated. Supported flags:
+`
+)
+
+var (
+	configFlag  = flag.String("config", "", "JSON file that defines the converter")
+	packageFlag = flag.String("package", os.Getenv("GOPACKAGE"), "package of the generated code, default: $GOPACKAGE")
+	outputFlag  = flag.String("output", "", "file to write, default hrid_gen.go, .ts or .sql; a Go test goes to the same name ending in _test.go")
+	prefixFlag  = flag.String("prefix", "", "prefix of the generated Go names, e.g. Order for OrderToString")
+	testFlag    = flag.Bool("test", true, "when true, a Go test is generated too")
+	langFlag    = flag.String("language", "go", "language to generate: go, typescript or postgresql")
+)
+
+// outputs are the default output files per language.
+var outputs = map[string]string{
+	"go":         "hrid_gen.go",
+	"typescript": "hrid_gen.ts",
+	"postgresql": "hrid_gen.sql",
+}
+
+// generators are the generators of languages other than Go, which have no generated test.
+var generators = map[string]func(*codegen.Config) ([]byte, *er.Err){
+	"typescript": codegen.TypeScript,
+	"postgresql": codegen.PostgreSQL,
+}
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, usage)
+		flag.PrintDefaults()
+		os.Exit(1)
+	}
+	flagnames.Patch()
+	flag.Parse()
+	output, ok := outputs[*langFlag]
+	if *configFlag == "" || !ok || (*langFlag == "go" && *packageFlag == "") || flag.NArg() > 0 {
+		flag.Usage()
+	}
+	if *outputFlag != "" {
+		output = *outputFlag
+	}
+	if err := generate(*configFlag, *langFlag, *packageFlag, *prefixFlag, output, *testFlag); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// generate is a helper that can be called from the unit test.
+func generate(config, lang, pkg, prefix, output string, test bool) error {
+	opts, idErr := id.LoadOpts(config)
+	if idErr != nil {
+		return idErr
+	}
+	c := &codegen.Config{
+		Opts:    opts,
+		Package: pkg,
+		Prefix:  prefix,
+	}
+	if gen, ok := generators[lang]; ok {
+		src, idErr := gen(c)
+		if idErr != nil {
+			return idErr
+		}
+		return os.WriteFile(output, src, 0644)
+	}
+	src, idErr := codegen.Go(c)
+	if idErr != nil {
+		return idErr
+	}
+	if err := os.WriteFile(output, src, 0644); err != nil {
+		return err
+	}
+	if !test {
+		return nil
+	}
+	src, idErr = codegen.GoTest(c)
+	if idErr != nil {
+		return idErr
+	}
+	return os.WriteFile(strings.TrimSuffix(output, ".go")+"_test.go", src, 0644)
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	dir := t.TempDir()
+	config := filepath.Join(dir, "hrid.json")
+	if err := os.WriteFile(config, []byte(`{"schema":1,"alphabet":"01","length":8,"checksum":1}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	output := filepath.Join(dir, "bin.go")
+	if err := generate(config, "go", "bin", "Bin", output, true); err != nil {
+		t.Fatalf("generate() = %v, want nil error", err)
+	}
+	for _, f := range []string{output, filepath.Join(dir, "bin_test.go")} {
+		if _, err := os.Stat(f); err != nil {
+			t.Errorf("generate() didn't write %v: %v", f, err)
+		}
+	}
+	for lang, file := range map[string]string{"typescript": "bin.ts", "postgresql": "bin.sql"} {
+		path := filepath.Join(dir, file)
+		if err := generate(config, lang, "", "Bin", path, true); err != nil {
+			t.Fatalf("generate() of %v = %v, want nil error", lang, err)
+		}
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("generate() didn't write %v: %v", path, err)
+		}
+	}
+	if err := generate(filepath.Join(dir, "missing.json"), "go", "bin", "", output, false); err == nil {
+		t.Errorf("generate() of a missing config = nil, want error")
+	}
+}
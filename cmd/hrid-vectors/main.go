@@ -0,0 +1,68 @@
+// Command hrid-vectors writes the conformance vectors of package vectors as JSON. The checked-in copy is refreshed
+// using:
+//
+//	go run ./cmd/hrid-vectors -output vectors/vectors.json
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/KarelKubat/flagnames"
+	"github.com/KarelKubat/hrid/vectors"
+)
+
+const (
+	usage = `
+This is hrid-vectors, which writes conformance test vectors for implementations of hrid.
+Usage:
+  hrid-vectors [-output FILE]
+
+The flags can be abbreviated. Supported flags:
+`
+)
+
+var (
+	outputFlag = flag.String("output", "", "file to write, default: stdout")
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, usage)
+		flag.PrintDefaults()
+		os.Exit(1)
+	}
+	flagnames.Patch()
+	flag.Parse()
+	if flag.NArg() > 0 {
+		flag.Usage()
+	}
+	w := io.Writer(os.Stdout)
+	if *outputFlag != "" {
+		f, err := os.Create(*outputFlag)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer f.Close()
+		w = f
+	}
+	if err := write(w); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// write is a helper that can be called from the unit test.
+func write(w io.Writer) error {
+	f, idErr := vectors.Generate()
+	if idErr != nil {
+		return idErr
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(f)
+}
@@ -0,0 +1,21 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"testing"
+)
+
+func TestWrite(t *testing.T) {
+	var buf bytes.Buffer
+	if err := write(&buf); err != nil {
+		t.Fatalf("write() = %v, want nil error", err)
+	}
+	want, err := os.ReadFile("../../vectors/vectors.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf.Bytes(), want) {
+		t.Errorf("write() differs from vectors/vectors.json")
+	}
+}
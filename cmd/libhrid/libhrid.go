@@ -0,0 +1,155 @@
+//go:build cgo
+
+// Command libhrid is a C shared library of package id, for programs that aren't written in Go. Build it with:
+//
+//	go build -buildmode=c-shared -o libhrid.so ./cmd/libhrid
+//
+// which also writes the C header libhrid.h. Converters are referred to by integer handles. All functions return an
+// error code, which matches er.Code; HRID_NONE is zero. IDs that hrid_to_string returns are allocated with malloc, and
+// must be released with free.
+package main
+
+/*
+#include <stdlib.h>
+
+// Error codes, matching er.Code of github.com/KarelKubat/hrid/er.
+typedef enum {
+	HRID_NONE = 0,
+	HRID_ALPHABET_TOO_SHORT_ERROR = 1,
+	HRID_TOKEN_REPEATS_ERROR = 2,
+	HRID_ID_TOO_SHORT_ERROR = 3,
+	HRID_CHECKSUM_ERROR = 4,
+	HRID_NO_SUCH_TOKEN_ERROR = 5,
+	HRID_BLOCKED_WORD_ERROR = 6,
+	HRID_RUN_TOO_LONG_ERROR = 7,
+	HRID_TOO_MANY_DERIVATIONS_ERROR = 8,
+	HRID_PATTERN_ERROR = 9,
+	HRID_OUT_OF_RANGE_ERROR = 10,
+	HRID_ID_TOO_LONG_ERROR = 11,
+	HRID_UNKNOWN_COUNTRY_ERROR = 12,
+	HRID_BBAN_LENGTH_ERROR = 13,
+	HRID_PREFIX_ERROR = 14,
+	HRID_REFERENCE_LENGTH_ERROR = 15,
+	HRID_VERSION_ERROR = 16,
+	HRID_UNKNOWN_VERSION_ERROR = 17,
+	HRID_NO_MATCH_ERROR = 18,
+	HRID_MASK_RUNE_ERROR = 19,
+	HRID_AMBIGUOUS_ERROR = 20,
+	HRID_DATA_TOO_LONG_ERROR = 21,
+	HRID_UNREPRESENTABLE_ERROR = 22,
+	HRID_CONFIG_ERROR = 23,
+	HRID_HANDLE_ERROR = 24,
+} hrid_error;
+*/
+import "C"
+
+import (
+	"sync"
+
+	"github.com/KarelKubat/hrid/er"
+	"github.com/KarelKubat/hrid/id"
+)
+
+var (
+	// mu protects converters and lastHandle, as C programs may call from several threads.
+	mu         sync.Mutex
+	converters = map[C.int]*id.ID{}
+	lastHandle C.int
+)
+
+// hrid_new instantiates a converter from a JSON configuration (see hrid -config), or with the defaults of package id
+// when config is NULL or empty. The handle of the converter is stored in *handle, which may not be NULL.
+//
+//export hrid_new
+func hrid_new(config *C.char, handle *C.int) C.int {
+	if handle == nil {
+		return C.int(er.HandleError)
+	}
+	opts := &id.Opts{
+		Alphabet:    id.Alphabet,
+		StringLen:   id.StringLen,
+		IgnoreCase:  id.IgnoreCase,
+		GroupSize:   id.GroupSize,
+		ChecksumLen: id.ChecksumLen,
+	}
+	if config != nil && C.GoString(config) != "" {
+		if err := opts.UnmarshalJSON([]byte(C.GoString(config))); err != nil {
+			return C.int(err.(*er.Err).Code)
+		}
+	}
+	c, err := id.New(opts)
+	if err != nil {
+		return C.int(err.Code)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	lastHandle++
+	converters[lastHandle] = c
+	*handle = lastHandle
+	return C.int(er.None)
+}
+
+// hrid_to_string stores the ID of n in *out, which may not be NULL. The ID must be released with free.
+//
+//export hrid_to_string
+func hrid_to_string(handle C.int, n C.ulonglong, out **C.char) C.int {
+	if out == nil {
+		return C.int(er.HandleError)
+	}
+	c, code := converter(handle)
+	if c == nil {
+		return code
+	}
+	s, err := c.ToCheckedString(uint64(n))
+	if err != nil {
+		return C.int(err.Code)
+	}
+	*out = C.CString(s)
+	return C.int(er.None)
+}
+
+// hrid_to_nr stores the number of the ID s in *out. Neither s nor out may be NULL.
+//
+//export hrid_to_nr
+func hrid_to_nr(handle C.int, s *C.char, out *C.ulonglong) C.int {
+	if s == nil || out == nil {
+		return C.int(er.HandleError)
+	}
+	c, code := converter(handle)
+	if c == nil {
+		return code
+	}
+	n, err := c.ToNr(C.GoString(s))
+	if err != nil {
+		return C.int(err.Code)
+	}
+	*out = C.ulonglong(n)
+	return C.int(er.None)
+}
+
+// hrid_free releases a converter. Its handle may not be used afterwards.
+//
+//export hrid_free
+func hrid_free(handle C.int) C.int {
+	mu.Lock()
+	defer mu.Unlock()
+	if _, ok := converters[handle]; !ok {
+		return C.int(er.HandleError)
+	}
+	delete(converters, handle)
+	return C.int(er.None)
+}
+
+// converter is a helper that looks up a handle.
+func converter(handle C.int) (*id.ID, C.int) {
+	mu.Lock()
+	defer mu.Unlock()
+	c, ok := converters[handle]
+	if !ok {
+		return nil, C.int(er.HandleError)
+	}
+	return c, C.int(er.None)
+}
+
+// main is required by -buildmode=c-shared.
+func main() {}
@@ -0,0 +1,73 @@
+//go:build cgo && linux
+
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/KarelKubat/hrid/er"
+)
+
+// TestLibrary builds the shared library, checks that the error codes of its header match er.Code, and runs
+// testdata/libhrid_test.c against it.
+func TestLibrary(t *testing.T) {
+	cc, err := exec.LookPath("cc")
+	if err != nil {
+		t.Skip("no C compiler")
+	}
+	dir := t.TempDir()
+	build := exec.Command("go", "build", "-buildmode=c-shared", "-o", filepath.Join(dir, "libhrid.so"), ".")
+	if out, err := build.CombinedOutput(); err != nil {
+		t.Fatalf("building the library failed: %v\n%s", err, out)
+	}
+
+	header, err := os.ReadFile(filepath.Join(dir, "libhrid.h"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	codes := map[string]int{}
+	for _, m := range regexp.MustCompile(`(HRID_\w+) = (\d+),`).FindAllStringSubmatch(string(header), -1) {
+		codes[m[1]], _ = strconv.Atoi(m[2])
+	}
+	for c := er.None; c < er.ZZLastUnused; c++ {
+		name := cName(c.String())
+		if got, ok := codes[name]; !ok || got != int(c) {
+			t.Errorf("libhrid.h has %v = %v,%v, want %v", name, got, ok, int(c))
+		}
+	}
+	if len(codes) != int(er.ZZLastUnused) {
+		t.Errorf("libhrid.h has %v error codes, want %v", len(codes), int(er.ZZLastUnused))
+	}
+
+	test := filepath.Join(dir, "libhrid_test")
+	compile := exec.Command(cc, "-Wall", "-o", test, filepath.Join("testdata", "libhrid_test.c"),
+		"-I", dir, "-L", dir, "-lhrid", "-Wl,-rpath,"+dir)
+	if out, err := compile.CombinedOutput(); err != nil {
+		t.Fatalf("compiling the C test failed: %v\n%s", err, out)
+	}
+	if out, err := exec.Command(test).CombinedOutput(); err != nil {
+		t.Errorf("the C test failed: %v\n%s", err, out)
+	}
+}
+
+// cName is a helper that converts the name of an er.Code to its C name, e.g. IDTooShortError to
+// HRID_ID_TOO_SHORT_ERROR.
+func cName(s string) string {
+	runes := []rune(s)
+	var b strings.Builder
+	b.WriteString("HRID")
+	for i, r := range runes {
+		upper := r >= 'A' && r <= 'Z'
+		if i == 0 || upper && (runes[i-1] >= 'a' || i+1 < len(runes) && runes[i+1] >= 'a') {
+			b.WriteString("_")
+		}
+		b.WriteString(strings.ToUpper(string(r)))
+	}
+	return b.String()
+}
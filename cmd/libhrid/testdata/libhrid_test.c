@@ -0,0 +1,74 @@
+// Test of libhrid, which is compiled and run by libhrid_test.go. It exits with 0 when all checks pass.
+#include <stdio.h>
+#include <stdlib.h>
+#include <string.h>
+
+#include "libhrid.h"
+
+static int failures = 0;
+
+static void check(int got, int want, const char *what) {
+	if (got != want) {
+		fprintf(stderr, "%s: got %d, want %d\n", what, got, want);
+		failures++;
+	}
+}
+
+static void check_string(int handle, unsigned long long n, const char *want) {
+	char *id = NULL;
+	check(hrid_to_string(handle, n, &id), HRID_NONE, "hrid_to_string");
+	if (id == NULL || strcmp(id, want) != 0) {
+		fprintf(stderr, "hrid_to_string(%llu): got \"%s\", want \"%s\"\n", n, id ? id : "(null)", want);
+		failures++;
+	}
+	free(id);
+}
+
+static void check_nr(int handle, const char *id, unsigned long long want, int want_code) {
+	unsigned long long n = 0;
+	check(hrid_to_nr(handle, id, &n), want_code, id);
+	if (want_code == HRID_NONE && n != want) {
+		fprintf(stderr, "hrid_to_nr(\"%s\"): got %llu, want %llu\n", id, n, want);
+		failures++;
+	}
+}
+
+int main(void) {
+	int defaults, versioned, bad;
+	unsigned long long nr;
+
+	check(hrid_new(NULL, &defaults), HRID_NONE, "hrid_new(NULL)");
+	check_string(defaults, 12, "000 000 000 000 CCR");
+	check_string(defaults, 18446744073709551615ULL, "QD0 75K B45 M86 FBP");
+	check_nr(defaults, "000 000 000 000 ccr", 12, HRID_NONE);
+	check_nr(defaults, "QD075KB45M86FBP", 18446744073709551615ULL, HRID_NONE);
+	check_nr(defaults, "000 000 000 000 001", 0, HRID_CHECKSUM_ERROR);
+	check_nr(defaults, "000 000 0!00 000 000", 0, HRID_NO_SUCH_TOKEN_ERROR);
+	check_nr(defaults, "", 0, HRID_ID_TOO_SHORT_ERROR);
+
+	check(hrid_new("{\"schema\":1,\"alphabet\":\"0123456789ABCDEFGHKLMNPQRTUVWXY\",\"length\":6,"
+		"\"ignorecase\":true,\"groupsize\":3,\"checksum\":1,\"version\":\"B\"}", &versioned),
+		HRID_NONE, "hrid_new(versioned)");
+	check_string(versioned, 12, "B 000 0CC");
+	check_nr(versioned, "b 000 0cc", 12, HRID_NONE);
+	check_nr(versioned, "A 000 0CC", 0, HRID_UNKNOWN_VERSION_ERROR);
+
+	check(hrid_new("{\"schema\":2}", &bad), HRID_CONFIG_ERROR, "hrid_new(schema 2)");
+	check(hrid_new("{\"schema\":1,\"alphabet\":\"a\"}", &bad), HRID_ALPHABET_TOO_SHORT_ERROR, "hrid_new(short)");
+
+	check(hrid_new(NULL, NULL), HRID_HANDLE_ERROR, "hrid_new(NULL, NULL)");
+	check(hrid_to_string(versioned, 12, NULL), HRID_HANDLE_ERROR, "hrid_to_string(NULL)");
+	check(hrid_to_nr(versioned, "B 000 0CC", NULL), HRID_HANDLE_ERROR, "hrid_to_nr(NULL)");
+	check(hrid_to_nr(versioned, NULL, &nr), HRID_HANDLE_ERROR, "hrid_to_nr(NULL ID)");
+
+	check(hrid_free(versioned), HRID_NONE, "hrid_free");
+	check(hrid_free(versioned), HRID_HANDLE_ERROR, "hrid_free twice");
+	check_nr(versioned, "B 000 0CC", 0, HRID_HANDLE_ERROR);
+	check(hrid_free(defaults), HRID_NONE, "hrid_free");
+
+	if (failures > 0) {
+		fprintf(stderr, "%d failures\n", failures);
+		return 1;
+	}
+	return 0;
+}
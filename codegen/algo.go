@@ -0,0 +1,252 @@
+package codegen
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/KarelKubat/hrid/er"
+	"github.com/KarelKubat/hrid/id"
+)
+
+// Op is an operation of an Algorithm.
+type Op int
+
+const (
+	// Encoding operations, which work on the runes of an ID.
+	OpDigits   Op = iota // Start with the digits of the number, most significant first
+	OpChecksum           // Append the checksum over all runes so far
+	OpPad                // Left-pad with the first token to N runes
+	OpGroup              // Split into groups of N runes, separated by a space
+	OpPrefix             // Prepend the version rune R, and a space when N is 1
+
+	// Decoding operations, which work on the runes of the input.
+	OpVersion // Drop leading whitespace, then rune R (after uppercasing when N is 1) or fail with UnknownVersionError
+	OpUpper   // Uppercase all runes
+	OpStrip   // Drop all whitespace
+	OpMinLen  // Fail with IDTooShortError when there are fewer than N runes
+	OpVerify  // Drop the last rune, and fail with ChecksumError when it's not the checksum of the runes before it
+	OpValue   // Return the value of the runes, or fail with NoSuchTokenError
+)
+
+// String stringifies an Op.
+func (o Op) String() string {
+	return []string{
+		"OpDigits", "OpChecksum", "OpPad", "OpGroup", "OpPrefix",
+		"OpVersion", "OpUpper", "OpStrip", "OpMinLen", "OpVerify", "OpValue",
+	}[o]
+}
+
+// Step is an operation with its arguments.
+type Step struct {
+	Op Op
+	N  int
+	R  rune
+}
+
+// Algorithm describes a converter as data: the alphabet, and the steps to encode and decode. It can be rendered in
+// other languages (see TypeScript) and interpreted in Go (see ToString and ToNr), so that the interpreter's tests
+// cover what the rendered code does.
+//
+// The checksum of runes is the sum of their values modulo the base; a rune that's not a token counts as zero. The
+// value of runes is computed modulo 2^64, as a uint64 wraps.
+type Algorithm struct {
+	Alphabet string
+	Encode   []Step
+	Decode   []Step
+}
+
+// NewAlgorithm returns the algorithm of an id.ID. Template-driven IDs aren't supported (PatternError).
+func NewAlgorithm(o *id.Opts) (*Algorithm, *er.Err) {
+	opts := *o
+	if _, err := id.New(&opts); err != nil { // Also normalises casing
+		return nil, err
+	}
+	if opts.Template != "" {
+		return nil, er.New(er.PatternError, "algorithms aren't supported for template-driven IDs")
+	}
+	a := convAlgorithm(opts.Alphabet, opts.ChecksumLen)
+	a.Encode = append(a.Encode, Step{Op: OpPad, N: opts.StringLen + opts.ChecksumLen - 1})
+	if opts.GroupSize > 0 {
+		a.Encode = append(a.Encode, Step{Op: OpGroup, N: opts.GroupSize})
+	}
+	if opts.Version != 0 {
+		sep := 0
+		if opts.GroupSize > 0 {
+			sep = 1
+		}
+		a.Encode = append(a.Encode, Step{Op: OpPrefix, R: opts.Version, N: sep})
+	}
+
+	decode := []Step{}
+	if opts.Version != 0 {
+		upper := 0
+		if opts.IgnoreCase {
+			upper = 1
+		}
+		decode = append(decode, Step{Op: OpVersion, R: opts.Version, N: upper})
+	}
+	if opts.IgnoreCase {
+		decode = append(decode, Step{Op: OpUpper})
+	}
+	if opts.GroupSize > 0 {
+		decode = append(decode, Step{Op: OpStrip})
+	}
+	a.Decode = append(decode, a.Decode...)
+	return a, nil
+}
+
+// convAlgorithm is a helper that returns the algorithm of a conv.Conv.
+func convAlgorithm(alphabet string, checksumLen int) *Algorithm {
+	a := &Algorithm{
+		Alphabet: alphabet,
+		Encode:   []Step{{Op: OpDigits}},
+		Decode:   []Step{{Op: OpMinLen, N: checksumLen + 1}},
+	}
+	for i := 0; i < checksumLen; i++ {
+		a.Encode = append(a.Encode, Step{Op: OpChecksum})
+		a.Decode = append(a.Decode, Step{Op: OpVerify})
+	}
+	a.Decode = append(a.Decode, Step{Op: OpValue})
+	return a
+}
+
+// ToString interprets the encoding steps.
+func (a *Algorithm) ToString(n uint64) string {
+	tokens := []rune(a.Alphabet)
+	base := uint64(len(tokens))
+	runes := []rune{}
+	for _, s := range a.Encode {
+		switch s.Op {
+		case OpDigits:
+			for runes = []rune{tokens[n%base]}; n >= base; runes = append([]rune{tokens[n%base]}, runes...) {
+				n /= base
+			}
+		case OpChecksum:
+			runes = append(runes, tokens[a.checksum(runes)])
+		case OpPad:
+			for len(runes) < s.N {
+				runes = append([]rune{tokens[0]}, runes...)
+			}
+		case OpGroup:
+			grouped := []rune{}
+			for i, r := range runes {
+				if i > 0 && i%s.N == 0 {
+					grouped = append(grouped, ' ')
+				}
+				grouped = append(grouped, r)
+			}
+			runes = grouped
+		case OpPrefix:
+			prefix := []rune{s.R}
+			if s.N == 1 {
+				prefix = append(prefix, ' ')
+			}
+			runes = append(prefix, runes...)
+		}
+	}
+	return string(runes)
+}
+
+// ToNr interprets the decoding steps.
+func (a *Algorithm) ToNr(s string) (uint64, *er.Err) {
+	runes := []rune(s)
+	for _, step := range a.Decode {
+		switch step.Op {
+		case OpVersion:
+			for len(runes) > 0 && unicode.IsSpace(runes[0]) {
+				runes = runes[1:]
+			}
+			if len(runes) == 0 {
+				return 0, er.Newf(er.UnknownVersionError, "ID %q doesn't start with version %v", s, string(step.R))
+			}
+			v := runes[0]
+			if step.N == 1 {
+				v = unicode.ToUpper(v)
+			}
+			if v != step.R {
+				return 0, er.Newf(er.UnknownVersionError, "ID %q doesn't start with version %v", s, string(step.R))
+			}
+			runes = runes[1:]
+		case OpUpper:
+			runes = []rune(strings.ToUpper(string(runes)))
+		case OpStrip:
+			kept := []rune{}
+			for _, r := range runes {
+				if !unicode.IsSpace(r) {
+					kept = append(kept, r)
+				}
+			}
+			runes = kept
+		case OpMinLen:
+			if len(runes) < step.N {
+				return 0, er.Newf(er.IDTooShortError, "ID %q has less than %v runes", s, step.N)
+			}
+		case OpVerify:
+			last := runes[len(runes)-1]
+			runes = runes[:len(runes)-1]
+			if want := []rune(a.Alphabet)[a.checksum(runes)]; last != want {
+				return 0, er.Newf(er.ChecksumError, "checksum error at %v, expected %v", string(last), string(want))
+			}
+		case OpValue:
+			base := uint64(len([]rune(a.Alphabet)))
+			out := uint64(0)
+			for _, r := range runes {
+				v, ok := a.value(r)
+				if !ok {
+					return 0, er.Newf(er.NoSuchTokenError, "token %v not in alphabet %q", string(r), a.Alphabet)
+				}
+				out = out*base + uint64(v)
+			}
+			return out, nil
+		}
+	}
+	return 0, er.New(er.PatternError, "algorithm has no value step")
+}
+
+// value is a helper that returns the value of a token.
+func (a *Algorithm) value(r rune) (int, bool) {
+	for i, t := range []rune(a.Alphabet) {
+		if t == r {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// checksum is a helper that returns the value of the checksum of runes.
+func (a *Algorithm) checksum(runes []rune) int {
+	sum := 0
+	for _, r := range runes {
+		v, _ := a.value(r)
+		sum += v
+	}
+	return sum % len([]rune(a.Alphabet))
+}
+
+// spaces is a helper that returns the runes that unicode.IsSpace accepts, for languages where whitespace differs.
+func spaces() []rune {
+	out := []rune{}
+	for r := rune(0); r <= unicode.MaxRune; r++ {
+		if unicode.IsSpace(r) {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// uppers is a helper that returns the runes that unicode.ToUpper maps to a token or to the version, and what they map
+// to. Uppercasing other runes doesn't matter, as they aren't tokens either way.
+func (a *Algorithm) uppers() (from, to []rune) {
+	targets := a.Alphabet
+	for _, s := range a.Decode {
+		if s.Op == OpVersion {
+			targets += string(s.R)
+		}
+	}
+	for r := rune(0); r <= unicode.MaxRune; r++ {
+		if u := unicode.ToUpper(r); u != r && strings.ContainsRune(targets, u) {
+			from, to = append(from, r), append(to, u)
+		}
+	}
+	return from, to
+}
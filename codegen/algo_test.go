@@ -0,0 +1,103 @@
+package codegen
+
+import (
+	"encoding/json"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/KarelKubat/hrid/er"
+	"github.com/KarelKubat/hrid/id"
+	"github.com/KarelKubat/hrid/vectors"
+)
+
+// TestVectors runs the conformance vectors through the interpreter. Since TypeScript and PostgreSQL render the same
+// algorithm, this covers the generated code too.
+func TestVectors(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("..", "vectors", "vectors.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := &vectors.File{}
+	if err := json.Unmarshal(data, f); err != nil {
+		t.Fatal(err)
+	}
+	suites := 0
+	for _, s := range f.Suites {
+		var a *Algorithm
+		switch {
+		case s.Conv != nil:
+			a = convAlgorithm(s.Conv.Alphabet, s.Conv.Checksum)
+		case s.ID.Template != "":
+			if _, err := NewAlgorithm(s.ID); err == nil || err.Code != er.PatternError {
+				t.Errorf("%v: NewAlgorithm() = _,%v, want PatternError", s.Name, err)
+			}
+			continue
+		default:
+			var err *er.Err
+			if a, err = NewAlgorithm(s.ID); err != nil {
+				t.Fatalf("%v: NewAlgorithm() returned unexpected error %v", s.Name, err)
+			}
+		}
+		suites++
+		for _, e := range s.Encode {
+			n, _ := strconv.ParseUint(e.Nr, 10, 64)
+			if got := a.ToString(n); got != e.ID {
+				t.Errorf("%v: ToString(%v) = %q, want %q", s.Name, e.Nr, got, e.ID)
+			}
+		}
+		for _, d := range s.Decode {
+			n, err := a.ToNr(d.ID)
+			gotNr, gotErr := "", ""
+			if err != nil {
+				gotErr = err.Code.String()
+			} else {
+				gotNr = strconv.FormatUint(n, 10)
+			}
+			if gotNr != d.Nr || gotErr != d.Error {
+				t.Errorf("%v: ToNr(%q) = %q,%q, want %q,%q", s.Name, d.ID, gotNr, gotErr, d.Nr, d.Error)
+			}
+		}
+	}
+	if suites == 0 {
+		t.Errorf("no suites were interpreted")
+	}
+}
+
+func TestAlgorithm(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, opts := range []*id.Opts{
+		{Alphabet: id.Alphabet, StringLen: id.StringLen, IgnoreCase: true, GroupSize: 3, ChecksumLen: 1},
+		{Alphabet: "αβγδε", StringLen: 10, IgnoreCase: true, GroupSize: 2, ChecksumLen: 2, Version: 'Ω'},
+		{Alphabet: "0123456789abcdef", ChecksumLen: 1, Version: 'v'},
+	} {
+		a, err := NewAlgorithm(opts)
+		if err != nil {
+			t.Fatalf("NewAlgorithm(%+v) returned unexpected error %v", opts, err)
+		}
+		c, err := id.New(opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < 1000; i++ {
+			n := rnd.Uint64() >> rnd.Intn(64)
+			want, _ := c.ToCheckedString(n)
+			if got := a.ToString(n); got != want {
+				t.Fatalf("ToString(%v) = %q, want %q", n, got, want)
+			}
+			if got, err := a.ToNr(want); err != nil || got != n {
+				t.Fatalf("ToNr(%q) = %v,%v, want %v", want, got, err, n)
+			}
+		}
+	}
+}
+
+func TestOpString(t *testing.T) {
+	for op := OpDigits; op <= OpValue; op++ {
+		if op.String() == "" {
+			t.Errorf("Op(%d).String() is empty", op)
+		}
+	}
+}
@@ -0,0 +1,387 @@
+// Package codegen generates Go source for converters whose options are compile-time constants. The generated code
+// has no dependencies beyond the standard library: it uses fixed-size lookup arrays instead of maps, and unrolled
+// encoding for the number of digits that a uint64 can have. Its IDs are the same as those of package id, and so are
+// the numbers that its ToNr returns. See cmd/hrid-gen for the go:generate tool.
+//
+// TypeScript modules and PostgreSQL functions are rendered from an Algorithm, a description of the converter as
+// steps, which can also be interpreted in Go so that the steps are tested against the conformance vectors.
+package codegen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"math"
+	"strings"
+	"text/template"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/KarelKubat/hrid/er"
+	"github.com/KarelKubat/hrid/id"
+)
+
+// Config describes what to generate.
+type Config struct {
+	Opts    *id.Opts // The converter options, which are validated by id.New.
+	Package string   // The package clause of the generated files.
+	Prefix  string   // Prefix of the generated names, so that one package can hold several converters.
+}
+
+// params are the values that the templates expand.
+type params struct {
+	Package   string
+	Prefix    string
+	Opts      string // JSON form of the options, for the header
+	Alphabet  string
+	First     string // First rune of the alphabet, as a Go literal
+	Base      int
+	Checksums []int // Positions in the buffer of the checksums
+	Digits    []int // Positions in the buffer of the digits of a uint64, least significant first
+	BufLen    int   // Length of the buffer: room for all digits and checksums, or for the padded length
+	Limit     int   // Highest start position in the buffer, so that at least one digit and the padding remain
+	GroupSize int
+	Version   string // Version rune as a Go literal, or empty
+	Ignore    bool
+	Index     []int8 // Value of each ASCII rune, or -1
+
+	// Options for the test, as Go literals.
+	OptsLiteral string
+}
+
+// Go returns the source of a converter: a constant alphabet, a lookup table, ToString and ToNr, all with names that
+// start with the prefix. Only converters without a template, and with an ASCII alphabet and version, are supported;
+// otherwise a PatternError occurs.
+func Go(c *Config) ([]byte, *er.Err) {
+	p, err := newParams(c)
+	if err != nil {
+		return nil, err
+	}
+	return expand(goTemplate, p)
+}
+
+// GoTest returns the source of a test for the code of Go, that checks that it is equivalent to package id for random
+// numbers and for variants of the generated IDs.
+func GoTest(c *Config) ([]byte, *er.Err) {
+	p, err := newParams(c)
+	if err != nil {
+		return nil, err
+	}
+	return expand(testTemplate, p)
+}
+
+// newParams is a helper that validates the options and computes what the templates need.
+func newParams(c *Config) (*params, *er.Err) {
+	o := *c.Opts
+	if _, err := id.New(&o); err != nil { // Also normalises casing
+		return nil, err
+	}
+	if o.Template != "" {
+		return nil, er.New(er.PatternError, "code generation isn't supported for template-driven IDs")
+	}
+	for _, r := range append([]rune(o.Alphabet), o.Version) {
+		if r >= utf8.RuneSelf {
+			return nil, er.Newf(er.PatternError, "code generation needs ASCII runes, not %q", r)
+		}
+	}
+	if c.Prefix != "" && !unicode.IsUpper([]rune(c.Prefix)[0]) {
+		return nil, er.Newf(er.PatternError, "prefix %q must start with an uppercase letter", c.Prefix)
+	}
+	js, _ := json.Marshal(&o)
+
+	p := &params{
+		Package:   c.Package,
+		Prefix:    c.Prefix,
+		Opts:      string(js),
+		Alphabet:  o.Alphabet,
+		First:     fmt.Sprintf("%q", o.Alphabet[0]),
+		Base:      len(o.Alphabet),
+		GroupSize: o.GroupSize,
+		Ignore:    o.IgnoreCase,
+		Index:     make([]int8, utf8.RuneSelf),
+		OptsLiteral: fmt.Sprintf("&id.Opts{Alphabet: %q, StringLen: %d, IgnoreCase: %v, GroupSize: %d, "+
+			"ChecksumLen: %d, Version: %q}", o.Alphabet, o.StringLen, o.IgnoreCase, o.GroupSize, o.ChecksumLen,
+			o.Version),
+	}
+	if o.Version != 0 {
+		p.Version = fmt.Sprintf("%q", o.Version)
+	}
+	digits := 0
+	for n := uint64(math.MaxUint64); n > 0; n /= uint64(p.Base) {
+		digits++
+	}
+	padded := o.StringLen + o.ChecksumLen - 1
+	p.BufLen = digits + o.ChecksumLen
+	if padded > p.BufLen {
+		p.BufLen = padded
+	}
+	for i := 0; i < digits; i++ {
+		p.Digits = append(p.Digits, p.BufLen-o.ChecksumLen-1-i)
+	}
+	for i := 0; i < o.ChecksumLen; i++ {
+		p.Checksums = append(p.Checksums, p.BufLen-o.ChecksumLen+i)
+	}
+	p.Limit = p.BufLen - o.ChecksumLen - 1
+	if p.BufLen-padded < p.Limit {
+		p.Limit = p.BufLen - padded
+	}
+
+	for r := range p.Index {
+		p.Index[r] = -1
+	}
+	for i, r := range o.Alphabet {
+		p.Index[r] = int8(i)
+		if o.IgnoreCase {
+			p.Index[unicode.ToLower(r)] = int8(i)
+		}
+	}
+	return p, nil
+}
+
+// expand is a helper that expands a template and formats the result.
+func expand(t *template.Template, p *params) ([]byte, *er.Err) {
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, p); err != nil {
+		return nil, er.Newf(er.PatternError, "cannot expand template: %v", err)
+	}
+	out, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, er.Newf(er.PatternError, "generated code doesn't compile: %v", err)
+	}
+	return out, nil
+}
+
+// funcs are the helpers that the templates use.
+var funcs = template.FuncMap{
+	"lower": func(s string) string {
+		if s == "" {
+			return "token"
+		}
+		return strings.ToLower(s[:1]) + s[1:]
+	},
+	"index": func(table []int8) string {
+		var b strings.Builder
+		for i, v := range table {
+			if i%16 == 0 {
+				b.WriteString("\n\t")
+			} else {
+				b.WriteString(" ")
+			}
+			fmt.Fprintf(&b, "%d,", v)
+		}
+		return b.String()
+	},
+}
+
+var goTemplate = template.Must(template.New("go").Funcs(funcs).Parse(`// Code generated by hrid-gen; DO NOT EDIT.
+
+// This converter is equivalent to package github.com/KarelKubat/hrid/id with the options:
+// {{.Opts}}
+
+package {{.Package}}
+
+import (
+	"errors"
+{{- if .Version}}
+	"strings"
+{{- end}}
+{{- if or .GroupSize .Ignore .Version}}
+	"unicode"
+{{- end}}
+{{- if or .Ignore .Version}}
+	"unicode/utf8"
+{{- end}}
+)
+
+// {{.Prefix}}Alphabet holds the tokens of IDs, in the order of their values.
+const {{.Prefix}}Alphabet = {{printf "%q" .Alphabet}}
+
+// Errors of {{.Prefix}}ToNr.
+var (
+	Err{{.Prefix}}TooShort    = errors.New("ID too short")
+	Err{{.Prefix}}NoSuchToken = errors.New("ID has a token that's not in the alphabet")
+	Err{{.Prefix}}Checksum    = errors.New("checksum error")
+{{- if .Version}}
+	Err{{.Prefix}}Version     = errors.New("ID doesn't start with version " + string({{.Version}}))
+{{- end}}
+)
+
+// {{lower .Prefix}}Index holds the value of each ASCII rune, or -1 for runes that aren't tokens.
+var {{lower .Prefix}}Index = [{{len .Index}}]int8{ {{- index .Index}}
+}
+
+// {{.Prefix}}ToString converts a uint64 to an ID.
+func {{.Prefix}}ToString(n uint64) string {
+	var buf [{{.BufLen}}]byte
+	for i := range buf {
+		buf[i] = {{.First}}
+	}
+
+	// Digits, least significant first.
+	var v uint64
+{{- if .Checksums}}
+	var sum uint64
+{{- end}}
+{{- range .Digits}}
+	v = n % {{$.Base}}
+	buf[{{.}}] = {{$.Prefix}}Alphabet[v]
+{{- if $.Checksums}}
+	sum += v
+{{- end}}
+	n /= {{$.Base}}
+{{- end}}
+{{- if .Checksums}}
+
+	// Checksums, each over all earlier runes.
+{{- range .Checksums}}
+	v = sum % {{$.Base}}
+	buf[{{.}}] = {{$.Prefix}}Alphabet[v]
+	sum += v
+{{- end}}
+{{- end}}
+
+{{- if .Limit}}
+
+	// Skip leading zero tokens, but keep one digit and the padding.
+	start := 0
+	for start < {{.Limit}} && buf[start] == {{.First}} {
+		start++
+	}
+{{- end}}
+{{- if .GroupSize}}
+
+	// Split into groups.
+	out := make([]byte, 0, 2+len(buf)*2)
+{{- if .Version}}
+	out = append(out, {{.Version}}, ' ')
+{{- end}}
+	for i := {{if .Limit}}start{{else}}0{{end}}; i < len(buf); i += {{.GroupSize}} {
+		if i > {{if .Limit}}start{{else}}0{{end}} {
+			out = append(out, ' ')
+		}
+		end := i + {{.GroupSize}}
+		if end > len(buf) {
+			end = len(buf)
+		}
+		out = append(out, buf[i:end]...)
+	}
+	return string(out)
+{{- else if .Version}}
+	return string({{.Version}}) + string(buf[{{if .Limit}}start{{end}}:])
+{{- else}}
+	return string(buf[{{if .Limit}}start{{end}}:])
+{{- end}}
+}
+
+// {{.Prefix}}ToNr converts an ID to a uint64.
+func {{.Prefix}}ToNr(s string) (uint64, error) {
+{{- if .Version}}
+	s = strings.TrimLeftFunc(s, unicode.IsSpace)
+	version, size := utf8.DecodeRuneInString(s)
+{{- if .Ignore}}
+	version = unicode.ToUpper(version)
+{{- end}}
+	if version != {{.Version}} {
+		return 0, Err{{.Prefix}}Version
+	}
+	s = s[size:]
+{{- end}}
+
+	var stack [{{.BufLen}}]uint8
+	values := stack[:0]
+	for _, r := range s {
+{{- if .GroupSize}}
+		if unicode.IsSpace(r) {
+			continue
+		}
+{{- end}}
+{{- if .Ignore}}
+		if r >= utf8.RuneSelf {
+			r = unicode.ToUpper(r)
+		}
+{{- end}}
+		if r >= {{len .Index}} || {{lower .Prefix}}Index[r] < 0 {
+			return 0, Err{{.Prefix}}NoSuchToken
+		}
+		values = append(values, uint8({{lower .Prefix}}Index[r]))
+	}
+	if len(values) <= {{len .Checksums}} {
+		return 0, Err{{.Prefix}}TooShort
+	}
+	digits := len(values) - {{len .Checksums}}
+{{- if .Checksums}}
+
+	sum := uint64(0)
+	for _, v := range values[:digits] {
+		sum = (sum + uint64(v)) % {{.Base}}
+	}
+	for _, v := range values[digits:] {
+		if uint64(v) != sum {
+			return 0, Err{{.Prefix}}Checksum
+		}
+		sum = (sum + uint64(v)) % {{.Base}}
+	}
+{{- end}}
+
+	out := uint64(0)
+	for _, v := range values[:digits] {
+		out = out*{{.Base}} + uint64(v)
+	}
+	return out, nil
+}
+`))
+
+var testTemplate = template.Must(template.New("test").Funcs(funcs).Parse(`// Code generated by hrid-gen; DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/KarelKubat/hrid/id"
+)
+
+func Test{{.Prefix}}Equivalence(t *testing.T) {
+	runtime, err := id.New({{.OptsLiteral}})
+	if err != nil {
+		t.Fatalf("id.New() returned unexpected error %v", err)
+	}
+	rnd := rand.New(rand.NewSource(1))
+	nrs := []uint64{0, 1, math.MaxUint64}
+	for i := 0; i < 10000; i++ {
+		nrs = append(nrs, rnd.Uint64()>>rnd.Intn(64))
+	}
+	for _, n := range nrs {
+		want := runtime.ToString(n)
+		if got := {{.Prefix}}ToString(n); got != want {
+			t.Fatalf("{{.Prefix}}ToString(%v) = %q, want %q", n, got, want)
+		}
+
+		// Mutate one rune, to try invalid tokens and checksum errors.
+		mutated := []rune(want)
+		mutated[rnd.Intn(len(mutated))] = rune({{.Prefix}}Alphabet[rnd.Intn(len({{.Prefix}}Alphabet))])
+		garbled := []rune(want)
+		garbled[rnd.Intn(len(garbled))] = rune(rnd.Intn(0x300))
+
+		for _, s := range []string{
+			want,
+			strings.ToLower(want),
+			strings.ReplaceAll(want, " ", ""),
+			" " + want + "\t",
+			string(mutated),
+			string(garbled),
+			want[:len(want)/2],
+		} {
+			wantNr, wantErr := runtime.ToNr(s)
+			gotNr, gotErr := {{.Prefix}}ToNr(s)
+			if (gotErr != nil) != (wantErr != nil) || wantErr == nil && gotNr != wantNr {
+				t.Fatalf("{{.Prefix}}ToNr(%q) = %v,%v, want %v,%v", s, gotNr, gotErr, wantNr, wantErr)
+			}
+		}
+	}
+}
+`))
@@ -0,0 +1,105 @@
+package codegen
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/KarelKubat/hrid/er"
+	"github.com/KarelKubat/hrid/id"
+)
+
+// TestGenerated checks that the generated examples in test/gen are up to date. Their own tests prove that they are
+// equivalent to package id.
+func TestGenerated(t *testing.T) {
+	for _, test := range []struct {
+		config, output, prefix string
+	}{
+		{config: "hrid.json", output: "ids"},
+		{config: "orders.json", output: "orders", prefix: "Order"},
+	} {
+		dir := filepath.Join("..", "test", "gen")
+		opts, err := id.LoadOpts(filepath.Join(dir, test.config))
+		if err != nil {
+			t.Fatalf("LoadOpts(%q) returned unexpected error %v", test.config, err)
+		}
+		c := &Config{Opts: opts, Package: "gen", Prefix: test.prefix}
+		for suffix, gen := range map[string]func(*Config) ([]byte, *er.Err){
+			".go": Go, "_test.go": GoTest, ".ts": TypeScript, ".sql": PostgreSQL,
+		} {
+			got, err := gen(c)
+			if err != nil {
+				t.Fatalf("generating %v%v returned unexpected error %v", test.output, suffix, err)
+			}
+			want, readErr := os.ReadFile(filepath.Join(dir, test.output+suffix))
+			if readErr != nil {
+				t.Fatal(readErr)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("%v%v is stale, run go generate in %v", test.output, suffix, dir)
+			}
+		}
+	}
+}
+
+func TestGoErrors(t *testing.T) {
+	for _, test := range []struct {
+		config   *Config
+		wantCode er.Code
+	}{
+		{
+			config:   &Config{Opts: &id.Opts{Alphabet: "0"}, Package: "p"},
+			wantCode: er.AlphabetTooShortError,
+		},
+		{
+			config:   &Config{Opts: &id.Opts{Template: "AA-99"}, Package: "p"},
+			wantCode: er.PatternError,
+		},
+		{
+			config:   &Config{Opts: &id.Opts{Alphabet: "0123456789äöü"}, Package: "p"},
+			wantCode: er.PatternError,
+		},
+		{
+			config:   &Config{Opts: &id.Opts{Alphabet: "01", Version: 'é'}, Package: "p"},
+			wantCode: er.PatternError,
+		},
+		{
+			config:   &Config{Opts: &id.Opts{Alphabet: "01"}, Package: "p", Prefix: "order"},
+			wantCode: er.PatternError,
+		},
+		{
+			config:   &Config{Opts: &id.Opts{Alphabet: "01"}, Package: "p", Prefix: "Order"},
+			wantCode: er.None,
+		},
+	} {
+		_, err := Go(test.config)
+		gotCode := er.None
+		if err != nil {
+			gotCode = err.Code
+		}
+		if gotCode != test.wantCode {
+			t.Errorf("Go(%+v) = _,%v, want error code %v", test.config.Opts, err, test.wantCode)
+		}
+	}
+}
+
+// TestGoSkipsZeros checks that the loop that skips leading zeros is only generated when there are zeros to skip.
+func TestGoSkipsZeros(t *testing.T) {
+	for _, test := range []struct {
+		opts *id.Opts
+		want bool
+	}{
+		{opts: &id.Opts{Alphabet: "0123456789ABCDEF", StringLen: 4, ChecksumLen: 1}, want: true},
+		{opts: &id.Opts{Alphabet: "0123456789ABCDEF", StringLen: 20, ChecksumLen: 1}, want: false},
+		{opts: &id.Opts{Alphabet: "0123456789ABCDEF", StringLen: 20, GroupSize: 4, Version: 'V'}, want: false},
+	} {
+		out, err := Go(&Config{Opts: test.opts, Package: "p"})
+		if err != nil {
+			t.Fatalf("Go(%+v) returned unexpected error %v", test.opts, err)
+		}
+		if got := bytes.Contains(out, []byte("start++")); got != test.want {
+			t.Errorf("Go(%+v) skips leading zeros: %v, want %v", test.opts, got, test.want)
+		}
+	}
+}
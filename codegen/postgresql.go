@@ -0,0 +1,414 @@
+package codegen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/KarelKubat/hrid/er"
+)
+
+// sqlParams are the values that the PostgreSQL template expands.
+type sqlParams struct {
+	Opts       string
+	Name       string // Prefix of the function names, e.g. "order_hrid"
+	Alphabet   string // SQL literals
+	Whitespace string
+	UpperFrom  string
+	UpperTo    string
+	Encode     []string // Statements of the encoding function
+	Decode     []string // Statements of the parsing function
+	Uses       map[string]bool
+	Check      []string // Lines of the CHECK expression in plain SQL
+	CheckRunes int
+}
+
+// PostgreSQL returns PL/pgSQL functions for a converter, rendered from the Algorithm of the options:
+//
+//   - hrid_encode(bigint) returns the ID of a number,
+//   - hrid_decode(text) returns the number of an ID, or raises invalid_text_representation,
+//   - hrid_validate(text) returns NULL for a valid ID, or the name of the error; it is meant for CHECK
+//     constraints such as CHECK (hrid_validate(id) IS NULL).
+//
+// A header comment holds a CHECK expression in plain SQL, which calls none of these functions: see sqlCheck.
+//
+// The names start with the lowercased prefix and an underscore, when a prefix is set. IDs encode unsigned 64-bit
+// numbers, which are stored in a bigint as two's complement: numbers of 2^63 and above are negative. Whitespace and
+// uppercasing are tables of what Go does, since PostgreSQL's depend on the locale.
+func PostgreSQL(c *Config) ([]byte, *er.Err) {
+	a, err := NewAlgorithm(c.Opts)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range c.Prefix {
+		if r >= unicode.MaxASCII || !(unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_') {
+			return nil, er.Newf(er.PatternError, "prefix %q isn't an SQL identifier", c.Prefix)
+		}
+	}
+	js, _ := json.Marshal(c.Opts)
+	from, to := a.uppers()
+	p := &sqlParams{
+		Opts:       string(js),
+		Name:       "hrid",
+		Alphabet:   sqlString(a.Alphabet),
+		Whitespace: sqlString(string(spaces())),
+		UpperFrom:  sqlString(string(from)),
+		UpperTo:    sqlString(string(to)),
+		Uses:       map[string]bool{},
+	}
+	if c.Prefix != "" {
+		p.Name = strings.ToLower(c.Prefix) + "_hrid"
+	}
+	for _, s := range a.Encode {
+		p.Encode = append(p.Encode, sqlStep(s, p.Name))
+		p.Uses[s.Op.String()] = true
+	}
+	for _, s := range a.Decode {
+		p.Decode = append(p.Decode, sqlStep(s, p.Name))
+		p.Uses[s.Op.String()] = true
+	}
+	check := newSQLCheck(a)
+	p.Check = check.sql(p)
+	p.CheckRunes = check.Runes
+
+	var buf bytes.Buffer
+	if err := sqlTemplate.Execute(&buf, p); err != nil {
+		return nil, er.Newf(er.PatternError, "cannot expand template: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// sqlStep is a helper that renders a step as PL/pgSQL statements. Encoding and parsing work on the text t, and on the
+// numeric v. The name prefixes the checksum function.
+func sqlStep(s Step, name string) string {
+	switch s.Op {
+	case OpDigits:
+		return "t := substr(alphabet, mod(v, base)::int + 1, 1);\n" +
+			"  v := div(v, base);\n" +
+			"  WHILE v > 0 LOOP\n" +
+			"    t := substr(alphabet, mod(v, base)::int + 1, 1) || t;\n" +
+			"    v := div(v, base);\n" +
+			"  END LOOP;"
+	case OpChecksum:
+		return fmt.Sprintf("t := t || substr(alphabet, %v_checksum(t) + 1, 1);", name)
+	case OpPad:
+		return fmt.Sprintf("WHILE char_length(t) < %d LOOP\n"+
+			"    t := substr(alphabet, 1, 1) || t;\n"+
+			"  END LOOP;", s.N)
+	case OpGroup:
+		return fmt.Sprintf("g := '';\n"+
+			"  FOR i IN 1..char_length(t) LOOP\n"+
+			"    IF i > 1 AND mod(i - 1, %d) = 0 THEN\n"+
+			"      g := g || ' ';\n"+
+			"    END IF;\n"+
+			"    g := g || substr(t, i, 1);\n"+
+			"  END LOOP;\n"+
+			"  t := g;", s.N)
+	case OpPrefix:
+		if s.N == 1 {
+			return fmt.Sprintf("t := %v || t;", sqlString(string(s.R)+" "))
+		}
+		return fmt.Sprintf("t := %v || t;", sqlString(string(s.R)))
+	case OpVersion:
+		first := "left(t, 1)"
+		if s.N == 1 {
+			first = "translate(left(t, 1), upper_from, upper_to)"
+		}
+		return fmt.Sprintf("t := ltrim(t, whitespace);\n"+
+			"  IF t = '' OR %v <> %v THEN\n"+
+			"    error := 'UnknownVersionError';\n"+
+			"    RETURN;\n"+
+			"  END IF;\n"+
+			"  t := substr(t, 2);", first, sqlString(string(s.R)))
+	case OpUpper:
+		return "t := translate(t, upper_from, upper_to);"
+	case OpStrip:
+		return "t := translate(t, whitespace, '');"
+	case OpMinLen:
+		return fmt.Sprintf("IF char_length(t) < %d THEN\n"+
+			"    error := 'IDTooShortError';\n"+
+			"    RETURN;\n"+
+			"  END IF;", s.N)
+	case OpVerify:
+		return fmt.Sprintf("got := right(t, 1);\n"+
+			"  t := left(t, -1);\n"+
+			"  IF got <> substr(alphabet, %v_checksum(t) + 1, 1) THEN\n"+
+			"    error := 'ChecksumError';\n"+
+			"    RETURN;\n"+
+			"  END IF;", name)
+	case OpValue:
+		return "FOR i IN 1..char_length(t) LOOP\n" +
+			"    d := strpos(alphabet, substr(t, i, 1));\n" +
+			"    IF d = 0 THEN\n" +
+			"      error := 'NoSuchTokenError';\n" +
+			"      RETURN;\n" +
+			"    END IF;\n" +
+			"    v := mod(v * base + d - 1, 18446744073709551616);\n" +
+			"  END LOOP;\n" +
+			"  IF v >= 9223372036854775808 THEN\n" +
+			"    v := v - 18446744073709551616;\n" +
+			"  END IF;\n" +
+			"  nr := v;"
+	}
+	return fmt.Sprintf("-- unknown operation %d", s.Op)
+}
+
+// sqlCheck describes a CHECK expression in plain SQL, without subqueries or calls of the generated functions: a
+// regular expression for the shape of an ID, and the checksums, unrolled over the runes of the normalised ID. The
+// runes are counted from the end, so that zeros that pad an ID don't shift them. Beyond Runes, the regular expression
+// only allows zeros; so IDs that overflow a uint64, which ToNr accepts as it wraps, are rejected.
+//
+// Each checksum is the sum of the runes before it modulo the base, which doubles with each checksum. So only the
+// first checksum is verified against the sum of the digits, and checksum k against 2^(k-1) times the first.
+type sqlCheck struct {
+	Pattern   string // Regular expression, in the syntax that PostgreSQL and Go share
+	Version   bool   // Drop leading whitespace and the version rune
+	Strip     bool   // Drop all whitespace
+	Upper     bool   // Uppercase, using the tables of uppers
+	Runes     int    // Most runes of an ID, not counting zeros that pad it
+	Checksums int
+	Base      int
+}
+
+// newSQLCheck returns the CHECK expression of an algorithm.
+func newSQLCheck(a *Algorithm) *sqlCheck {
+	c := &sqlCheck{Base: len([]rune(a.Alphabet))}
+	for n := uint64(math.MaxUint64); n > 0; n /= uint64(c.Base) {
+		c.Runes++
+	}
+	for _, s := range a.Encode {
+		if s.Op == OpChecksum {
+			c.Runes++
+			c.Checksums++
+		}
+		if s.Op == OpPad && s.N > c.Runes {
+			c.Runes = s.N
+		}
+	}
+
+	from, to := a.uppers()
+	// variants are the runes that become r, when uppercasing.
+	variants := func(r rune, upper bool) string {
+		out := string(r)
+		for i := range from {
+			if upper && to[i] == r {
+				out += string(from[i])
+			}
+		}
+		return out
+	}
+	ws := regexpClass(string(spaces()))
+	minLen := 1
+	version := ""
+	for _, s := range a.Decode {
+		switch s.Op {
+		case OpVersion:
+			c.Version = true
+			version = ws + "*" + regexpClass(variants(s.R, s.N == 1))
+		case OpUpper:
+			c.Upper = true
+		case OpStrip:
+			c.Strip = true
+		case OpMinLen:
+			minLen = s.N
+		}
+	}
+	tokens := ""
+	for _, r := range a.Alphabet {
+		tokens += variants(r, c.Upper)
+	}
+	zero := regexpClass(variants([]rune(a.Alphabet)[0], c.Upper))
+	if c.Strip {
+		c.Pattern = fmt.Sprintf("^%v%v*(?:%v%v*)*(?:%v%v*){%d,%d}$",
+			version, ws, zero, ws, regexpClass(tokens), ws, minLen, c.Runes)
+	} else {
+		c.Pattern = fmt.Sprintf("^%v%v*%v{%d,%d}$", version, zero, regexpClass(tokens), minLen, c.Runes)
+	}
+	return c
+}
+
+// sql returns the lines of the expression, which tests VALUE as in CREATE DOMAIN.
+func (c *sqlCheck) sql(p *sqlParams) []string {
+	lines := []string{"VALUE ~ " + sqlString(c.Pattern)}
+	if c.Checksums == 0 {
+		return lines
+	}
+	t := "VALUE"
+	if c.Version {
+		t = fmt.Sprintf("substr(ltrim(%v, %v), 2)", t, p.Whitespace)
+	}
+	if c.Strip {
+		t = fmt.Sprintf("translate(%v, %v, '')", t, p.Whitespace)
+	}
+	if c.Upper {
+		t = fmt.Sprintf("translate(%v, %v, %v)", t, p.UpperFrom, p.UpperTo)
+	}
+	value := func(i int) string {
+		return fmt.Sprintf("greatest(strpos(%v, substr(reverse(%v), %d, 1)) - 1, 0)", p.Alphabet, t, i)
+	}
+	lines = append(lines, fmt.Sprintf("AND %v = mod(", value(c.Checksums)))
+	for i := c.Checksums + 1; i <= c.Runes; i++ {
+		sep := "+ "
+		if i == c.Checksums+1 {
+			sep = ""
+		}
+		lines = append(lines, "  "+sep+value(i))
+	}
+	lines = append(lines, fmt.Sprintf(", %d)", c.Base))
+	factor := 1
+	for i := c.Checksums - 1; i >= 1; i-- {
+		factor = factor * 2 % c.Base
+		if factor == 0 {
+			lines = append(lines, fmt.Sprintf("AND %v = 0", value(i)))
+		} else {
+			lines = append(lines, fmt.Sprintf("AND %v = mod(%d * %v, %d)", value(i), factor, value(c.Checksums), c.Base))
+		}
+	}
+	return lines
+}
+
+// regexpClass is a helper that returns a character class of runes, in the syntax that PostgreSQL and Go share.
+func regexpClass(runes string) string {
+	var b strings.Builder
+	b.WriteString("[")
+	for _, r := range runes {
+		if strings.ContainsRune(`\[]^-`, r) {
+			b.WriteRune('\\')
+		}
+		b.WriteRune(r)
+	}
+	b.WriteString("]")
+	return b.String()
+}
+
+// sqlString is a helper that quotes a string for SQL. Strings beyond printable ASCII are Unicode escape strings, so
+// that the SQL file is ASCII.
+func sqlString(s string) string {
+	var b strings.Builder
+	escaped := false
+	for _, r := range s {
+		switch {
+		case r == '\'':
+			b.WriteString("''")
+		case r == '\\':
+			b.WriteString(`\\`)
+		case r >= ' ' && r < unicode.MaxASCII:
+			b.WriteRune(r)
+		case r <= 0xFFFF:
+			fmt.Fprintf(&b, `\%04X`, r)
+			escaped = true
+		default:
+			fmt.Fprintf(&b, `\+%06X`, r)
+			escaped = true
+		}
+	}
+	if escaped {
+		return "U&'" + b.String() + "'"
+	}
+	// Without escapes, a backslash is an ordinary character.
+	return "'" + strings.ReplaceAll(b.String(), `\\`, `\`) + "'"
+}
+
+var sqlTemplate = template.Must(template.New("sql").Parse(`-- Code generated by hrid-gen; DO NOT EDIT.
+
+-- These functions are equivalent to package github.com/KarelKubat/hrid/id with the options:
+-- {{.Opts}}
+-- Numbers are unsigned 64-bit numbers, stored in a bigint as two's complement.
+-- For a CHECK constraint, use: CHECK ({{.Name}}_validate(column) IS NULL)
+-- This expression calls the functions below, which must exist before the constraint is added. The expression below
+-- is plain SQL, which calls none of them. It is written for CREATE DOMAIN ... AS text; in a table, replace VALUE by
+-- the column. Unlike {{.Name}}_validate, it rejects IDs of more than {{.CheckRunes}} runes, unless they are padded with zeros.
+-- CHECK (
+{{- range .Check}}
+--   {{.}}
+{{- end}}
+-- )
+
+-- {{.Name}}_checksum returns the sum of the values of the runes of t modulo the base. Runes that aren't tokens count
+-- as zero.
+CREATE OR REPLACE FUNCTION {{.Name}}_checksum(t text) RETURNS int
+LANGUAGE plpgsql IMMUTABLE STRICT PARALLEL SAFE AS $$
+DECLARE
+  alphabet CONSTANT text := {{.Alphabet}};
+  total int := 0;
+BEGIN
+  FOR i IN 1..char_length(t) LOOP
+    total := total + greatest(strpos(alphabet, substr(t, i, 1)) - 1, 0);
+  END LOOP;
+  RETURN mod(total, char_length(alphabet));
+END;
+$$;
+
+-- {{.Name}}_encode returns the ID of a number.
+CREATE OR REPLACE FUNCTION {{.Name}}_encode(n bigint) RETURNS text
+LANGUAGE plpgsql IMMUTABLE STRICT PARALLEL SAFE AS $$
+DECLARE
+  alphabet CONSTANT text := {{.Alphabet}};
+  base CONSTANT int := char_length(alphabet);
+  v numeric := n;
+  t text;
+{{- if .Uses.OpGroup}}
+  g text;
+{{- end}}
+BEGIN
+  IF v < 0 THEN
+    v := v + 18446744073709551616;
+  END IF;
+  {{- range .Encode}}
+  {{.}}
+  {{- end}}
+  RETURN t;
+END;
+$$;
+
+-- {{.Name}}_parse returns the number of an ID, or the name of the error.
+CREATE OR REPLACE FUNCTION {{.Name}}_parse(s text, OUT nr bigint, OUT error text)
+LANGUAGE plpgsql IMMUTABLE STRICT PARALLEL SAFE AS $$
+DECLARE
+  alphabet CONSTANT text := {{.Alphabet}};
+  base CONSTANT int := char_length(alphabet);
+{{- if or .Uses.OpVersion .Uses.OpStrip}}
+  whitespace CONSTANT text := {{.Whitespace}};
+{{- end}}
+{{- if .Uses.OpUpper}}
+  upper_from CONSTANT text := {{.UpperFrom}};
+  upper_to CONSTANT text := {{.UpperTo}};
+{{- end}}
+  t text := s;
+  v numeric := 0;
+  d int;
+{{- if .Uses.OpVerify}}
+  got text;
+{{- end}}
+BEGIN
+  {{- range .Decode}}
+  {{.}}
+  {{- end}}
+END;
+$$;
+
+-- {{.Name}}_decode returns the number of an ID, or raises invalid_text_representation.
+CREATE OR REPLACE FUNCTION {{.Name}}_decode(s text) RETURNS bigint
+LANGUAGE plpgsql IMMUTABLE STRICT PARALLEL SAFE AS $$
+DECLARE
+  r record;
+BEGIN
+  SELECT * INTO r FROM {{.Name}}_parse(s);
+  IF r.error IS NOT NULL THEN
+    RAISE EXCEPTION USING ERRCODE = 'invalid_text_representation', MESSAGE = format('%s: invalid ID %L', r.error, s);
+  END IF;
+  RETURN r.nr;
+END;
+$$;
+
+-- {{.Name}}_validate returns NULL for a valid ID, or the name of the error. It is an SQL wrapper around
+-- {{.Name}}_parse, for CHECK constraints.
+CREATE OR REPLACE FUNCTION {{.Name}}_validate(s text) RETURNS text
+LANGUAGE sql IMMUTABLE STRICT PARALLEL SAFE AS $$
+  SELECT error FROM {{.Name}}_parse(s);
+$$;
+`))
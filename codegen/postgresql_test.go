@@ -0,0 +1,208 @@
+package codegen
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"unicode"
+
+	"github.com/KarelKubat/hrid/er"
+	"github.com/KarelKubat/hrid/id"
+	"github.com/KarelKubat/hrid/vectors"
+)
+
+func TestPostgreSQL(t *testing.T) {
+	for _, test := range []struct {
+		config      *Config
+		wantLines   []string
+		unwantLines []string
+	}{
+		{
+			config: &Config{Opts: &id.Opts{Alphabet: "ABC", StringLen: 6, IgnoreCase: true, GroupSize: 2, ChecksumLen: 1,
+				Version: 'x'}},
+			wantLines: []string{
+				`CREATE OR REPLACE FUNCTION hrid_encode(n bigint) RETURNS text`,
+				`CREATE OR REPLACE FUNCTION hrid_decode(s text) RETURNS bigint`,
+				`CREATE OR REPLACE FUNCTION hrid_validate(s text) RETURNS text`,
+				`  alphabet CONSTANT text := 'ABC';`,
+				`  upper_from CONSTANT text := 'abcx';`,
+				`  upper_to CONSTANT text := 'ABCX';`,
+				`  t := t || substr(alphabet, hrid_checksum(t) + 1, 1);`,
+				`  WHILE char_length(t) < 6 LOOP`,
+				`    IF i > 1 AND mod(i - 1, 2) = 0 THEN`,
+				`  t := 'X ' || t;`,
+				`  IF t = '' OR translate(left(t, 1), upper_from, upper_to) <> 'X' THEN`,
+				`  t := translate(t, upper_from, upper_to);`,
+				`  t := translate(t, whitespace, '');`,
+				`  IF char_length(t) < 2 THEN`,
+				`  IF got <> substr(alphabet, hrid_checksum(t) + 1, 1) THEN`,
+				`  nr := v;`,
+				`--   , 3)`,
+			},
+		},
+		{
+			config: &Config{Opts: &id.Opts{Alphabet: "0123456789", StringLen: 4}, Prefix: "Order"},
+			wantLines: []string{
+				`CREATE OR REPLACE FUNCTION order_hrid_encode(n bigint) RETURNS text`,
+				`  SELECT error FROM order_hrid_parse(s);`,
+				`--   VALUE ~ '^[0]*[0123456789]{1,20}$'`,
+			},
+			unwantLines: []string{
+				`  g text;`,
+				`  got text;`,
+				`  upper_from CONSTANT text := '';`,
+			},
+		},
+		{
+			config: &Config{Opts: &id.Opts{Alphabet: "01'\\é\U0001F600", StringLen: 4}},
+			wantLines: []string{
+				`  alphabet CONSTANT text := U&'01''\\\00E9\+01F600';`,
+			},
+		},
+		{
+			config: &Config{Opts: &id.Opts{Alphabet: "01'\\", StringLen: 4}},
+			wantLines: []string{
+				`  alphabet CONSTANT text := '01''\';`,
+			},
+		},
+	} {
+		src, err := PostgreSQL(test.config)
+		if err != nil {
+			t.Fatalf("PostgreSQL(%+v) returned unexpected error %v", test.config, err)
+		}
+		lines := map[string]bool{}
+		for _, l := range strings.Split(string(src), "\n") {
+			lines[l] = true
+		}
+		for _, l := range test.wantLines {
+			if !lines[l] {
+				t.Errorf("PostgreSQL(%+v) lacks line %q", test.config, l)
+			}
+		}
+		for _, l := range test.unwantLines {
+			if lines[l] {
+				t.Errorf("PostgreSQL(%+v) has unexpected line %q", test.config, l)
+			}
+		}
+	}
+}
+
+func TestPostgreSQLErrors(t *testing.T) {
+	for _, test := range []struct {
+		config   *Config
+		wantCode er.Code
+	}{
+		{config: &Config{Opts: &id.Opts{Alphabet: "0"}}, wantCode: er.AlphabetTooShortError},
+		{config: &Config{Opts: &id.Opts{Template: "AA-99"}}, wantCode: er.PatternError},
+		{config: &Config{Opts: &id.Opts{Alphabet: "01"}, Prefix: "Order-"}, wantCode: er.PatternError},
+		{config: &Config{Opts: &id.Opts{Alphabet: "01"}, Prefix: "Ördre"}, wantCode: er.PatternError},
+	} {
+		if _, err := PostgreSQL(test.config); err == nil || err.Code != test.wantCode {
+			t.Errorf("PostgreSQL(%+v) = _,%v, want error code %v", test.config, err, test.wantCode)
+		}
+	}
+}
+
+// TestSQLCheck runs the conformance vectors through an interpreter of the CHECK expression, which does what the
+// PostgreSQL functions in it do.
+func TestSQLCheck(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("..", "vectors", "vectors.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := &vectors.File{}
+	if err := json.Unmarshal(data, f); err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range f.Suites {
+		var a *Algorithm
+		switch {
+		case s.Conv != nil:
+			a = convAlgorithm(s.Conv.Alphabet, s.Conv.Checksum)
+		case s.ID.Template != "":
+			continue
+		default:
+			var err *er.Err
+			if a, err = NewAlgorithm(s.ID); err != nil {
+				t.Fatalf("%v: NewAlgorithm() returned unexpected error %v", s.Name, err)
+			}
+		}
+		c := newSQLCheck(a)
+		for _, d := range s.Decode {
+			if got, want := c.check(a, d.ID), d.Error == ""; got != want {
+				t.Errorf("%v: CHECK of %q = %v, want %v (%v)", s.Name, d.ID, got, want, d.Error)
+			}
+		}
+	}
+
+	// Zeros that pad an ID.
+	opts := &id.Opts{Alphabet: id.Alphabet, StringLen: id.StringLen, IgnoreCase: true, GroupSize: 3, ChecksumLen: 2}
+	a, _ := NewAlgorithm(opts)
+	c := newSQLCheck(a)
+	for _, test := range []struct {
+		s    string
+		want bool
+	}{
+		{s: "000 000 CNH M74 XCQ Y4Q H24", want: true},
+		{s: "100 000 CNH M74 XCQ Y4Q H24", want: false},
+		{s: "000 000 CNH M74 XCQ Y4Q H25", want: false},
+	} {
+		if got := c.check(a, test.s); got != test.want {
+			t.Errorf("CHECK of %q = %v, want %v", test.s, got, test.want)
+		}
+	}
+}
+
+// check is a helper that interprets the CHECK expression.
+func (c *sqlCheck) check(a *Algorithm, s string) bool {
+	if !regexp.MustCompile(c.Pattern).MatchString(s) {
+		return false
+	}
+	if c.Checksums == 0 {
+		return true
+	}
+	if c.Version {
+		s = strings.TrimLeftFunc(s, unicode.IsSpace)
+		s = string([]rune(s)[1:])
+	}
+	if c.Strip {
+		s = strings.Join(strings.Fields(s), "")
+	}
+	runes := []rune(s)
+	if c.Upper {
+		from, to := a.uppers()
+		for i, r := range runes {
+			for j := range from {
+				if r == from[j] {
+					runes[i] = to[j]
+				}
+			}
+		}
+	}
+	// value is the value of rune i, counted from the end and from 1; runes beyond the start count as zero.
+	value := func(i int) int {
+		if i > len(runes) {
+			return 0
+		}
+		v, _ := a.value(runes[len(runes)-i])
+		return v
+	}
+	sum := 0
+	for i := c.Checksums + 1; i <= c.Runes; i++ {
+		sum += value(i)
+	}
+	if value(c.Checksums) != sum%c.Base {
+		return false
+	}
+	factor := 1
+	for i := c.Checksums - 1; i >= 1; i-- {
+		factor = factor * 2 % c.Base
+		if value(i) != factor*value(c.Checksums)%c.Base {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,251 @@
+package codegen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/KarelKubat/hrid/er"
+)
+
+// tsParams are the values that the TypeScript template expands.
+type tsParams struct {
+	Opts       string
+	Alphabet   string          // Tokens as a TypeScript array
+	Whitespace string          // Runes that unicode.IsSpace accepts, as a TypeScript array
+	Upper      string          // Runes that unicode.ToUpper maps to a token or the version, as a TypeScript array of pairs
+	Encode     []string        // Statements of toString
+	Decode     []string        // Statements of toNr
+	Uses       map[string]bool // Names of the operations that occur
+}
+
+// TypeScript returns the source of a dependency-free TypeScript module with toString, toNr (using BigInt) and
+// validate, rendered from the Algorithm of the options. Its IDs are the same as those of package id; Package and
+// Prefix of the configuration aren't used, as the module is its own namespace. Whitespace and uppercasing are tables
+// of what Go does, since JavaScript's \s and toUpperCase differ for some runes.
+func TypeScript(c *Config) ([]byte, *er.Err) {
+	a, err := NewAlgorithm(c.Opts)
+	if err != nil {
+		return nil, err
+	}
+	js, _ := json.Marshal(c.Opts)
+	p := &tsParams{
+		Opts:     string(js),
+		Alphabet: tsArray(strings.Split(a.Alphabet, "")),
+		Uses:     map[string]bool{},
+	}
+	space, upper := []string{}, []string{}
+	for _, r := range spaces() {
+		space = append(space, string(r))
+	}
+	from, to := a.uppers()
+	for i := range from {
+		upper = append(upper, tsArray([]string{string(from[i]), string(to[i])}))
+	}
+	p.Whitespace = tsArray(space)
+	p.Upper = "[" + strings.Join(upper, ", ") + "]"
+	for _, s := range a.Encode {
+		p.Encode = append(p.Encode, tsStep(s))
+		p.Uses[s.Op.String()] = true
+	}
+	for _, s := range a.Decode {
+		p.Decode = append(p.Decode, tsStep(s))
+		p.Uses[s.Op.String()] = true
+	}
+
+	var buf bytes.Buffer
+	if err := tsTemplate.Execute(&buf, p); err != nil {
+		return nil, er.Newf(er.PatternError, "cannot expand template: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// tsStep is a helper that renders a step as a TypeScript statement.
+func tsStep(s Step) string {
+	switch s.Op {
+	case OpDigits:
+		return "let runes = digits(v);"
+	case OpChecksum:
+		return "runes.push(ALPHABET[checksum(runes)]);"
+	case OpPad:
+		return fmt.Sprintf("while (runes.length < %d) runes.unshift(ALPHABET[0]);", s.N)
+	case OpGroup:
+		return fmt.Sprintf("runes = group(runes, %d);", s.N)
+	case OpPrefix:
+		if s.N == 1 {
+			return fmt.Sprintf("runes.unshift(%v, \" \");", tsString(string(s.R)))
+		}
+		return fmt.Sprintf("runes.unshift(%v);", tsString(string(s.R)))
+	case OpVersion:
+		return fmt.Sprintf("runes = version(runes, s, %v);", tsString(string(s.R)))
+	case OpUpper:
+		return "runes = runes.map(upper);"
+	case OpStrip:
+		return "runes = runes.filter((r) => !WHITESPACE.has(r));"
+	case OpMinLen:
+		return fmt.Sprintf("if (runes.length < %d) throw new HridError(\"IDTooShortError\", `ID ${JSON.stringify(s)} "+
+			"has less than %d runes`);", s.N, s.N)
+	case OpVerify:
+		return "verify(runes);"
+	case OpValue:
+		return "return value(runes);"
+	}
+	return fmt.Sprintf("// unknown operation %d", s.Op)
+}
+
+// tsString is a helper that quotes a string for TypeScript, escaping all but printable ASCII.
+func tsString(s string) string {
+	var b strings.Builder
+	b.WriteString(`"`)
+	for _, r := range s {
+		switch {
+		case r == '"' || r == '\\':
+			b.WriteString(`\` + string(r))
+		case r >= ' ' && r < unicode.MaxASCII:
+			b.WriteRune(r)
+		case r <= 0xFFFF:
+			fmt.Fprintf(&b, `\u%04x`, r)
+		default:
+			fmt.Fprintf(&b, `\u{%x}`, r)
+		}
+	}
+	b.WriteString(`"`)
+	return b.String()
+}
+
+// tsArray is a helper that renders strings as a TypeScript array.
+func tsArray(strs []string) string {
+	quoted := []string{}
+	for _, s := range strs {
+		quoted = append(quoted, tsString(s))
+	}
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
+
+var tsTemplate = template.Must(template.New("ts").Parse(`// Code generated by hrid-gen; DO NOT EDIT.
+
+// This module is equivalent to package github.com/KarelKubat/hrid/id with the options:
+// {{.Opts}}
+// Numbers are BigInts, since IDs represent unsigned 64-bit numbers.
+
+/** The tokens of IDs, by value. */
+export const ALPHABET: readonly string[] = {{.Alphabet}};
+
+const INDEX: ReadonlyMap<string, number> = new Map(ALPHABET.map((t, i) => [t, i]));
+const BASE = BigInt(ALPHABET.length);
+const MAX = 0xffffffffffffffffn;
+{{- if or (index .Uses "OpVersion") (index .Uses "OpStrip")}}
+const WHITESPACE: ReadonlySet<string> = new Set({{.Whitespace}});
+{{- end}}
+{{- if index .Uses "OpUpper"}}
+// UPPER maps runes to the tokens (or the version) that they uppercase to.
+const UPPER: ReadonlyMap<string, string> = new Map({{.Upper}});
+{{- end}}
+
+/** The error of toNr. The code is the name of the error code of package er, e.g. "ChecksumError". */
+export class HridError extends Error {
+  readonly code: string;
+
+  constructor(code: string, message: string) {
+    super(message);
+    this.name = "HridError";
+    this.code = code;
+  }
+}
+
+/** Returns the ID of a number between 0 and 2^64-1. */
+export function toString(n: bigint | number): string {
+  const v = BigInt(n);
+  if (v < 0n || v > MAX) throw new RangeError(` + "`${n} is not an unsigned 64-bit number`" + `);
+  {{- range .Encode}}
+  {{.}}
+  {{- end}}
+  return runes.join("");
+}
+
+/** Returns the number of an ID, or throws a HridError. */
+export function toNr(s: string): bigint {
+  let runes = Array.from(s);
+  {{- range .Decode}}
+  {{.}}
+  {{- end}}
+}
+
+/** Returns null when an ID is valid, or the code of the HridError that toNr throws. */
+export function validate(s: string): string | null {
+  try {
+    toNr(s);
+    return null;
+  } catch (e) {
+    if (e instanceof HridError) return e.code;
+    throw e;
+  }
+}
+
+function digits(v: bigint): string[] {
+  const runes = [ALPHABET[Number(v % BASE)]];
+  for (v /= BASE; v > 0n; v /= BASE) runes.unshift(ALPHABET[Number(v % BASE)]);
+  return runes;
+}
+
+// checksum returns the sum of the values of runes modulo the base. Runes that aren't tokens count as zero.
+function checksum(runes: string[]): number {
+  let sum = 0;
+  for (const r of runes) sum += INDEX.get(r) ?? 0;
+  return sum % ALPHABET.length;
+}
+{{- if index .Uses "OpGroup"}}
+
+function group(runes: string[], size: number): string[] {
+  const out: string[] = [];
+  runes.forEach((r, i) => {
+    if (i > 0 && i % size === 0) out.push(" ");
+    out.push(r);
+  });
+  return out;
+}
+{{- end}}
+{{- if index .Uses "OpUpper"}}
+
+// upper uppercases a rune, as far as it matters: other runes aren't tokens either way.
+function upper(r: string): string {
+  return UPPER.get(r) ?? r;
+}
+{{- end}}
+{{- if index .Uses "OpVersion"}}
+
+function version(runes: string[], s: string, v: string): string[] {
+  let i = 0;
+  while (i < runes.length && WHITESPACE.has(runes[i])) i++;
+  if (i === runes.length || {{if index .Uses "OpUpper"}}upper(runes[i]){{else}}runes[i]{{end}} !== v) {
+    throw new HridError("UnknownVersionError", ` + "`ID ${JSON.stringify(s)} doesn't start with version ${v}`" + `);
+  }
+  return runes.slice(i + 1);
+}
+{{- end}}
+{{- if index .Uses "OpVerify"}}
+
+// verify drops the last rune, which must be the checksum of the runes before it.
+function verify(runes: string[]): void {
+  const last = runes.pop() as string;
+  const want = ALPHABET[checksum(runes)];
+  if (last !== want) throw new HridError("ChecksumError", ` + "`checksum error at ${last}, expected ${want}`" + `);
+}
+{{- end}}
+
+// value returns the number of runes, modulo 2^64 as in Go.
+function value(runes: string[]): bigint {
+  let out = 0n;
+  for (const r of runes) {
+    const v = INDEX.get(r);
+    if (v === undefined) {
+      throw new HridError("NoSuchTokenError", ` + "`token ${r} not in alphabet ${JSON.stringify(ALPHABET.join(\"\"))}`" + `);
+    }
+    out = BigInt.asUintN(64, out * BASE + BigInt(v));
+  }
+  return out;
+}
+`))
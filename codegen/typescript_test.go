@@ -0,0 +1,87 @@
+package codegen
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/KarelKubat/hrid/er"
+	"github.com/KarelKubat/hrid/id"
+)
+
+func TestTypeScript(t *testing.T) {
+	for _, test := range []struct {
+		opts        *id.Opts
+		wantLines   []string
+		unwantLines []string
+	}{
+		{
+			opts: &id.Opts{Alphabet: "ABC", StringLen: 6, IgnoreCase: true, GroupSize: 2, ChecksumLen: 1, Version: 'x'},
+			wantLines: []string{
+				`export const ALPHABET: readonly string[] = ["A", "B", "C"];`,
+				`const UPPER: ReadonlyMap<string, string> = new Map([["a", "A"], ["b", "B"], ["c", "C"], ["x", "X"]]);`,
+				`  let runes = digits(v);`,
+				`  runes.push(ALPHABET[checksum(runes)]);`,
+				`  while (runes.length < 6) runes.unshift(ALPHABET[0]);`,
+				`  runes = group(runes, 2);`,
+				`  runes.unshift("X", " ");`,
+				`  runes = version(runes, s, "X");`,
+				`  runes = runes.map(upper);`,
+				`  runes = runes.filter((r) => !WHITESPACE.has(r));`,
+				`  verify(runes);`,
+				`  return value(runes);`,
+				`  if (i === runes.length || upper(runes[i]) !== v) {`,
+			},
+		},
+		{
+			opts: &id.Opts{Alphabet: "0123456789", StringLen: 4},
+			wantLines: []string{
+				`  while (runes.length < 3) runes.unshift(ALPHABET[0]);`,
+			},
+			unwantLines: []string{
+				`function group(runes: string[], size: number): string[] {`,
+				`function upper(r: string): string {`,
+				`function verify(runes: string[]): void {`,
+				`function version(runes: string[], s: string, v: string): string[] {`,
+			},
+		},
+		{
+			opts: &id.Opts{Alphabet: "01\"\\é\U0001F600", StringLen: 4},
+			wantLines: []string{
+				`export const ALPHABET: readonly string[] = ["0", "1", "\"", "\\", "\u00e9", "\u{1f600}"];`,
+			},
+		},
+	} {
+		src, err := TypeScript(&Config{Opts: test.opts})
+		if err != nil {
+			t.Fatalf("TypeScript(%+v) returned unexpected error %v", test.opts, err)
+		}
+		lines := map[string]bool{}
+		for _, l := range strings.Split(string(src), "\n") {
+			lines[l] = true
+		}
+		for _, l := range test.wantLines {
+			if !lines[l] {
+				t.Errorf("TypeScript(%+v) lacks line %q", test.opts, l)
+			}
+		}
+		for _, l := range test.unwantLines {
+			if lines[l] {
+				t.Errorf("TypeScript(%+v) has unexpected line %q", test.opts, l)
+			}
+		}
+	}
+}
+
+func TestTypeScriptErrors(t *testing.T) {
+	for _, test := range []struct {
+		opts     *id.Opts
+		wantCode er.Code
+	}{
+		{opts: &id.Opts{Alphabet: "0"}, wantCode: er.AlphabetTooShortError},
+		{opts: &id.Opts{Template: "AA-99"}, wantCode: er.PatternError},
+	} {
+		if _, err := TypeScript(&Config{Opts: test.opts}); err == nil || err.Code != test.wantCode {
+			t.Errorf("TypeScript(%+v) = _,%v, want error code %v", test.opts, err, test.wantCode)
+		}
+	}
+}
@@ -41,6 +41,25 @@ func (a *Conv) FirstRune() rune {
 	return a.alphabet[0]
 }
 
+// Alphabet returns the tokens of the alphabet, in the order of their values.
+func (a *Conv) Alphabet() string {
+	return string(a.alphabet)
+}
+
+// Base returns the number of tokens in the alphabet, which is the base of the number system.
+func (a *Conv) Base() int {
+	return a.tokenLen
+}
+
+// Index returns the numeric value of a token. An error occurs when the token is not in the alphabet.
+func (a *Conv) Index(r rune) (int, *er.Err) {
+	index, ok := a.tokenIndex[r]
+	if !ok {
+		return 0, er.Newf(er.NoSuchTokenError, "token %v not in alphabet %q", string(r), string(a.alphabet))
+	}
+	return index, nil
+}
+
 // ToRunes converts a uint64 to runes representation and adds checksum runes if so requested.
 func (a *Conv) ToRunes(nr uint64) []rune {
 	reversed := []rune{}
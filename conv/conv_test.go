@@ -236,3 +236,22 @@ func TestChecksum(t *testing.T) {
 		}
 	}
 }
+
+func TestIndex(t *testing.T) {
+	a, err := New("0123456789ABCDEF", 0)
+	if err != nil {
+		t.Fatalf("New(0-F) returned unexpected error %v", err)
+	}
+	if a.Base() != 16 {
+		t.Errorf("a.Base() = %v, want 16", a.Base())
+	}
+	if a.Alphabet() != "0123456789ABCDEF" {
+		t.Errorf("a.Alphabet() = %q, want \"0123456789ABCDEF\"", a.Alphabet())
+	}
+	if index, err := a.Index('C'); err != nil || index != 12 {
+		t.Errorf("a.Index('C') = %v,%v, want 12,nil", index, err)
+	}
+	if _, err := a.Index('Z'); err == nil {
+		t.Errorf("a.Index('Z') = _,nil, want error")
+	}
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"unicode"
+	"unicode/utf8"
+)
+
+// editor reads lines from a terminal in raw mode. It supports the usual keys: arrows, Home and End (or Ctrl-A and
+// Ctrl-E), Backspace and Delete, Ctrl-K and Ctrl-U to kill to the end or start of the line, Up and Down to browse the
+// history, Ctrl-C to discard the line and Ctrl-D to stop. While a line is typed, live renders it with a hint.
+type editor struct {
+	r       *bufio.Reader
+	w       io.Writer
+	prompt  string
+	live    func(line string) (shown, hint string) // When nil, lines are shown as typed
+	history []string                               // Earlier lines, the oldest first
+
+	line []rune // The line being edited
+	pos  int    // The position of the cursor in line
+}
+
+// Keys that the editor handles, as a terminal in raw mode sends them.
+const (
+	keyCtrlA     = 1
+	keyCtrlB     = 2
+	keyCtrlC     = 3
+	keyCtrlD     = 4
+	keyCtrlE     = 5
+	keyCtrlF     = 6
+	keyBackspace = 8
+	keyCtrlK     = 11
+	keyCtrlU     = 21
+	keyEscape    = 27
+	keyDelete    = 127
+)
+
+// readLine reads a line. It returns io.EOF when Ctrl-D is pressed on an empty line, or when the input ends.
+func (e *editor) readLine() (string, error) {
+	e.line, e.pos = nil, 0
+	browsing := len(e.history) // The index in history of the shown line, or len(history) for the new line
+	var edited []rune          // The new line, while browsing the history
+	e.redraw(true)
+	for {
+		r, _, err := e.r.ReadRune()
+		if err != nil {
+			return "", err
+		}
+		switch r {
+		case '\r', '\n':
+			e.redraw(false)
+			fmt.Fprint(e.w, "\n")
+			return string(e.line), nil
+		case keyCtrlC:
+			fmt.Fprint(e.w, "^C\n")
+			e.line, e.pos = nil, 0
+			browsing = len(e.history)
+		case keyCtrlD:
+			if len(e.line) == 0 {
+				fmt.Fprint(e.w, "\n")
+				return "", io.EOF
+			}
+			e.delete()
+		case keyCtrlA:
+			e.pos = 0
+		case keyCtrlE:
+			e.pos = len(e.line)
+		case keyCtrlB:
+			e.left()
+		case keyCtrlF:
+			e.right()
+		case keyCtrlK:
+			e.line = e.line[:e.pos]
+		case keyCtrlU:
+			e.line, e.pos = e.line[e.pos:], 0
+		case keyBackspace, keyDelete:
+			if e.pos > 0 {
+				e.pos--
+				e.delete()
+			}
+		case keyEscape:
+			switch e.escape() {
+			case 'A':
+				if browsing > 0 {
+					if browsing == len(e.history) {
+						edited = e.line
+					}
+					browsing--
+					e.line = []rune(e.history[browsing])
+					e.pos = len(e.line)
+				}
+			case 'B':
+				if browsing < len(e.history) {
+					browsing++
+					e.line = edited
+					if browsing < len(e.history) {
+						e.line = []rune(e.history[browsing])
+					}
+					e.pos = len(e.line)
+				}
+			case 'C':
+				e.right()
+			case 'D':
+				e.left()
+			case 'H':
+				e.pos = 0
+			case 'F':
+				e.pos = len(e.line)
+			case '3':
+				e.delete()
+			}
+		default:
+			if unicode.IsPrint(r) {
+				e.line = append(e.line[:e.pos], append([]rune{r}, e.line[e.pos:]...)...)
+				e.pos++
+			}
+		}
+		e.redraw(true)
+	}
+}
+
+// escape is a helper that reads the rest of an escape sequence, and returns its final rune: A to D for the arrows,
+// H and F for Home and End, 3 for Delete. Other keys yield zero. A terminal sends a sequence at once, so when nothing
+// follows, Esc was pressed by itself and is ignored, rather than taking the next key.
+func (e *editor) escape() rune {
+	if e.r.Buffered() == 0 {
+		return 0
+	}
+	r, _, err := e.r.ReadRune()
+	if err != nil || r != '[' && r != 'O' {
+		return 0
+	}
+	r, _, err = e.r.ReadRune()
+	if err != nil {
+		return 0
+	}
+	if r < '0' || r > '9' {
+		return r
+	}
+	// Sequences such as ESC [ 3 ~ end in a tilde.
+	code := r
+	for r != '~' {
+		if r, _, err = e.r.ReadRune(); err != nil {
+			return 0
+		}
+	}
+	switch code {
+	case '1', '7':
+		return 'H'
+	case '4', '8':
+		return 'F'
+	case '3':
+		return '3'
+	}
+	return 0
+}
+
+// left moves the cursor one rune to the left.
+func (e *editor) left() {
+	if e.pos > 0 {
+		e.pos--
+	}
+}
+
+// right moves the cursor one rune to the right.
+func (e *editor) right() {
+	if e.pos < len(e.line) {
+		e.pos++
+	}
+}
+
+// delete removes the rune under the cursor.
+func (e *editor) delete() {
+	if e.pos < len(e.line) {
+		e.line = append(e.line[:e.pos], e.line[e.pos+1:]...)
+	}
+}
+
+// redraw shows the prompt, the line and, when withHint, its hint; and puts the cursor in place.
+func (e *editor) redraw(withHint bool) {
+	shown, hint := string(e.line), ""
+	if e.live != nil {
+		shown, hint = e.live(string(e.line))
+	}
+	fmt.Fprintf(e.w, "\r\x1b[K%v%v", e.prompt, shown)
+	if hint != "" && withHint {
+		fmt.Fprintf(e.w, "  %v", hint)
+	}
+	fmt.Fprint(e.w, "\r")
+	if col := utf8.RuneCountInString(e.prompt) + e.pos; col > 0 {
+		fmt.Fprintf(e.w, "\x1b[%dC", col)
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestEditor(t *testing.T) {
+	for _, test := range []struct {
+		keys    string
+		history []string
+		want    string
+		wantErr error
+	}{
+		{keys: "12\r", want: "12"},
+		{keys: "13\x1b[D\x1b[D2\r", want: "213"},
+		{keys: "abc\x01x\x05y\r", want: "xabcy"},
+		{keys: "abc\x02\x02\x06\x7f\r", want: "ac"},
+		{keys: "abc\x1b[D\x1b[D\x1b[3~\r", want: "ac"},
+		{keys: "abc\x1b[H\x04\x1b[F!\r", want: "bc!"},
+		{keys: "abcd\x1b[D\x1b[D\x0b\r", want: "ab"},
+		{keys: "abcd\x1b[D\x15\r", want: "d"},
+		{keys: "abc\x03xyz\r", want: "xyz"},
+		{keys: "a\x1b[1~b\x1b[4~c\r", want: "bac"},
+		{keys: "\x1b[A\r", history: []string{"one", "two"}, want: "two"},
+		{keys: "\x1b[A\x1b[A\x1b[A\r", history: []string{"one", "two"}, want: "one"},
+		{keys: "new\x1b[A\x1b[B\r", history: []string{"one", "two"}, want: "new"},
+		{keys: "\x1b[A!\r", history: []string{"one"}, want: "one!"},
+		{keys: "\x04", wantErr: io.EOF},
+		{keys: "unfinished", wantErr: io.EOF},
+	} {
+		var out strings.Builder
+		e := &editor{r: bufio.NewReader(strings.NewReader(test.keys)), w: &out, prompt: "> ", history: test.history}
+		got, err := e.readLine()
+		if got != test.want || err != test.wantErr {
+			t.Errorf("readLine() of keys %q = %q,%v, want %q,%v", test.keys, got, err, test.want, test.wantErr)
+		}
+	}
+}
+
+func TestEditorLoneEscape(t *testing.T) {
+	// Keys arrive one at a time, as when they are typed, so that an Esc isn't followed by a sequence.
+	var out strings.Builder
+	e := &editor{r: bufio.NewReader(iotest.OneByteReader(strings.NewReader("\x1bab\r"))), w: &out, prompt: "> "}
+	if got, err := e.readLine(); got != "ab" || err != nil {
+		t.Errorf("readLine() of a lone Esc and ab = %q,%v, want \"ab\",nil", got, err)
+	}
+}
+
+func TestEditorLive(t *testing.T) {
+	var out strings.Builder
+	e := &editor{
+		r:      bufio.NewReader(strings.NewReader("ab\r")),
+		w:      &out,
+		prompt: "> ",
+		live: func(line string) (string, string) {
+			return strings.ToUpper(line), "hint for " + line
+		},
+	}
+	if _, err := e.readLine(); err != nil {
+		t.Fatalf("readLine() = _,%v, want nil error", err)
+	}
+	// Each key redraws the line with its hint, and the final line has no hint.
+	want := "\r\x1b[K>   hint for \r\x1b[2C" +
+		"\r\x1b[K> A  hint for a\r\x1b[3C" +
+		"\r\x1b[K> AB  hint for ab\r\x1b[4C" +
+		"\r\x1b[K> AB\r\x1b[4C\n"
+	if out.String() != want {
+		t.Errorf("readLine() wrote %q, want %q", out.String(), want)
+	}
+}
@@ -16,6 +16,25 @@ const (
 	IDTooShortError
 	ChecksumError
 	NoSuchTokenError
+	BlockedWordError
+	RunTooLongError
+	TooManyDerivationsError
+	PatternError
+	OutOfRangeError
+	IDTooLongError
+	UnknownCountryError
+	BBANLengthError
+	PrefixError
+	ReferenceLengthError
+	VersionError
+	UnknownVersionError
+	NoMatchError
+	MaskRuneError
+	AmbiguousError
+	DataTooLongError
+	UnrepresentableError
+	ConfigError
+	HandleError
 
 	ZZLastUnused // Keep at last slot for test coverage
 )
@@ -30,6 +49,25 @@ func (c Code) String() string {
 		"IDTooShortError",
 		"ChecksumError",
 		"NoSuchTokenError",
+		"BlockedWordError",
+		"RunTooLongError",
+		"TooManyDerivationsError",
+		"PatternError",
+		"OutOfRangeError",
+		"IDTooLongError",
+		"UnknownCountryError",
+		"BBANLengthError",
+		"PrefixError",
+		"ReferenceLengthError",
+		"VersionError",
+		"UnknownVersionError",
+		"NoMatchError",
+		"MaskRuneError",
+		"AmbiguousError",
+		"DataTooLongError",
+		"UnrepresentableError",
+		"ConfigError",
+		"HandleError",
 	}[c]
 }
 
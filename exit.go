@@ -0,0 +1,37 @@
+package main
+
+import "github.com/KarelKubat/hrid/er"
+
+// Exit statuses of hrid.
+const (
+	exitOK      = iota // All inputs are converted
+	exitInvalid        // Some inputs are invalid, e.g. an ID with a checksum error
+	exitUsage          // Flags or arguments are wrong
+	exitConfig         // The converter can't be instantiated, e.g. the alphabet is too short
+	exitFailure        // Other failures, e.g. stdin can't be read
+)
+
+// configCodes are the error codes that stem from the definition of a converter, rather than from what it converts:
+// the programming errors of the README.
+var configCodes = map[er.Code]bool{
+	er.AlphabetTooShortError: true,
+	er.TokenRepeatsError:     true,
+	er.PatternError:          true,
+	er.VersionError:          true,
+	er.ConfigError:           true,
+	er.HandleError:           true,
+	er.MaskRuneError:         true,
+}
+
+// exitCode returns the exit status for an error: exitConfig when the converter is wrongly defined, exitInvalid
+// otherwise, or exitOK for nil.
+func exitCode(err *er.Err) int {
+	switch {
+	case err == nil:
+		return exitOK
+	case configCodes[err.Code]:
+		return exitConfig
+	default:
+		return exitInvalid
+	}
+}
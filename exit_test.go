@@ -0,0 +1,23 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/KarelKubat/hrid/er"
+)
+
+func TestExitCode(t *testing.T) {
+	for _, test := range []struct {
+		err  *er.Err
+		want int
+	}{
+		{err: nil, want: exitOK},
+		{err: er.New(er.ChecksumError, "checksum"), want: exitInvalid},
+		{err: er.New(er.AlphabetTooShortError, "alphabet"), want: exitConfig},
+		{err: er.New(er.ConfigError, "config"), want: exitConfig},
+	} {
+		if got := exitCode(test.err); got != test.want {
+			t.Errorf("exitCode(%v) = %v, want %v", test.err, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+
+	"github.com/KarelKubat/hrid/barcode"
+	"github.com/KarelKubat/hrid/id"
+)
+
+// Modes of hrid: what it does, given the subcommand, the first argument and the flags.
+const (
+	modeIBAN        = "iban"
+	modeRF          = "rf"
+	modeGrep        = "grep"
+	modeInspect     = "inspect"
+	modeEmit        = "-emit"
+	modeInteractive = "-i"
+	modeBatch       = "-batch"
+	modeArgs        = "arguments"
+)
+
+// modeFlags are the flags that only have an effect in some modes. hrid rejects them in the other modes, rather than
+// silently ignoring them. The converter flags and -verbose aren't listed, since iban and rf accept them too.
+var modeFlags = []struct {
+	name  string
+	modes []string
+}{
+	{"id", []string{modeIBAN, modeRF, modeBatch, modeArgs}},
+	{"profile", []string{modeInteractive, modeBatch, modeArgs}},
+	{"output", []string{modeInspect, modeBatch, modeArgs}},
+	{"spell", []string{modeBatch, modeArgs}},
+	{"dialect", []string{modeBatch, modeArgs}},
+	{"qr", []string{modeArgs}},
+	{"barcode", []string{modeArgs}},
+	{"format", []string{modeArgs}},
+	{"emit", []string{modeEmit}},
+	{"i", []string{modeInteractive}},
+	{"batch", []string{modeBatch}},
+	{"keepgoing", []string{modeBatch}},
+}
+
+// mode returns what hrid does. The first argument iban or rf takes precedence, then -emit, the subcommand inspect,
+// the first argument grep, -i and -batch, in that order. Otherwise, the arguments are converted.
+func mode(cmd string, args []string) string {
+	switch {
+	case len(args) > 0 && (args[0] == modeIBAN || args[0] == modeRF):
+		return args[0]
+	case *emitFlag != "":
+		return modeEmit
+	case cmd == modeInspect:
+		return modeInspect
+	case len(args) > 0 && args[0] == modeGrep:
+		return modeGrep
+	case *replFlag:
+		return modeInteractive
+	case *batchFlag:
+		return modeBatch
+	}
+	return modeArgs
+}
+
+// setFlags returns the names of the flags that are given, also when they are given with their default value.
+func setFlags() map[string]bool {
+	set := map[string]bool{}
+	flag.Visit(func(f *flag.Flag) {
+		set[f.Name] = true
+	})
+	return set
+}
+
+// checkFlags returns an error when the flags and arguments can't be combined in mode m, or when a flag is given that
+// has no effect.
+func checkFlags(cmd, m string, args []string, decode bool) error {
+	set := setFlags()
+	for _, f := range modeFlags {
+		if set[f.name] && !has(f.modes, m) {
+			return fmt.Errorf("-%v has no effect with %v", f.name, m)
+		}
+	}
+	switch {
+	case cmd == "encode" && *idFlag:
+		return errors.New("encode converts numbers, and can't be combined with -id")
+	case cmd == modeInspect && len(args) > 0:
+		return errors.New("inspect describes the converter, and takes no arguments")
+	case cmd == "validate" && (*batchFlag || *outputFlag != "text"):
+		return errors.New("validate only sets the exit status, and can't be combined with -batch or -output")
+	case cmd != "" && len(args) > 0 && (args[0] == modeIBAN || args[0] == modeRF || args[0] == modeGrep):
+		return fmt.Errorf("%v can't be combined with the subcommand %v", args[0], cmd)
+	case *replFlag && (len(args) > 0 || cmd != "" || *batchFlag || *outputFlag != "text"):
+		return errors.New("-i reads stdin, and can't be combined with arguments, subcommands, -batch or -output")
+	case m == modeIBAN || m == modeRF:
+		return nil
+	case cmd != "" && cmd != m && m != modeBatch && m != modeArgs:
+		return fmt.Errorf("%v can't be combined with the subcommand %v", m, cmd)
+	case m == modeGrep && len(args) > 1:
+		return errors.New("grep reads stdin, and takes no arguments")
+	case m == modeEmit && len(args) > 0:
+		return errors.New("-emit describes the converter, and takes no arguments")
+	case decode && (set["spell"] || set["dialect"] || set["qr"] || set["barcode"] || set["format"]):
+		return errors.New("-spell, -dialect, -qr, -barcode and -format apply to generated IDs, and can't be " +
+			"combined with decoding")
+	case !decode && m != modeInteractive && set["profile"]:
+		return errors.New("-profile adds converters for decoding, and needs -id")
+	case set["dialect"] && !set["spell"]:
+		return errors.New("-dialect needs -spell")
+	case set["format"] && !set["barcode"]:
+		return errors.New("-format needs -barcode")
+	case set["spell"] && (set["qr"] || set["barcode"]):
+		return errors.New("-spell can't be combined with -qr or -barcode")
+	}
+
+	switch *emitFlag {
+	case "", "regex", "jsonschema":
+	default:
+		return fmt.Errorf("-emit %q is not supported, use regex or jsonschema", *emitFlag)
+	}
+	if m == modeEmit {
+		return nil
+	}
+	if !outputs[*outputFlag] {
+		return fmt.Errorf("-output %q is not supported, use text, json or jsonl", *outputFlag)
+	}
+	if m == modeInspect || m == modeGrep {
+		return nil
+	}
+	if _, ok := id.Phonetics[*dialectFlag]; !ok {
+		return fmt.Errorf("-dialect %q is not supported, use en or nl", *dialectFlag)
+	}
+	switch *qrFlag {
+	case "", "term":
+	case "png", "svg":
+		if decode || len(args) != 1 {
+			return fmt.Errorf("-qr %v writes one image and needs exactly one number", *qrFlag)
+		}
+	default:
+		return fmt.Errorf("-qr %q is not supported, use png, svg or term", *qrFlag)
+	}
+	if *barcodeFlag != "" {
+		if _, ok := barcode.Symbologies[*barcodeFlag]; !ok {
+			return fmt.Errorf("-barcode %q is not supported, use code128 or code39", *barcodeFlag)
+		}
+		if *formatFlag != "svg" && *formatFlag != "png" {
+			return fmt.Errorf("-format %q is not supported, use svg or png", *formatFlag)
+		}
+		if decode || len(args) != 1 || *qrFlag != "" {
+			return errors.New("-barcode writes one image and needs exactly one number, and no -qr")
+		}
+	}
+	if *outputFlag != "text" && (*qrFlag != "" || *barcodeFlag != "") {
+		return fmt.Errorf("-output %v can't be combined with -qr or -barcode", *outputFlag)
+	}
+	if *batchFlag && (len(args) > 0 || *qrFlag != "" || *barcodeFlag != "") {
+		return errors.New("-batch reads stdin, and can't be combined with arguments, -qr or -barcode")
+	}
+	return nil
+}
+
+// has is a helper that returns whether a list of strings holds s.
+func has(list []string, s string) bool {
+	for _, l := range list {
+		if l == s {
+			return true
+		}
+	}
+	return false
+}
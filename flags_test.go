@@ -0,0 +1,80 @@
+package main
+
+import (
+	"flag"
+	"strings"
+	"testing"
+)
+
+func TestFlagsAreChecked(t *testing.T) {
+	// The converter flags and -verbose have an effect in all modes; the others must be in modeFlags.
+	known := map[string]bool{
+		"alphabet": true, "length": true, "ignorecase": true, "groupsize": true, "checksum": true, "template": true,
+		"config": true, "verbose": true,
+	}
+	for _, f := range modeFlags {
+		known[f.name] = true
+	}
+	flag.VisitAll(func(f *flag.Flag) {
+		if !known[f.Name] && !strings.HasPrefix(f.Name, "test.") {
+			t.Errorf("flag -%v is missing in modeFlags", f.Name)
+		}
+	})
+}
+
+func TestCheckFlags(t *testing.T) {
+	for _, test := range []struct {
+		flags   map[string]string
+		args    []string
+		wantErr bool
+	}{
+		{args: []string{"12"}},
+		{flags: map[string]string{"spell": "true", "dialect": "nl"}, args: []string{"12"}},
+		{flags: map[string]string{"id": "true", "profile": "checksum=0"}, args: []string{"CNH"}},
+		{flags: map[string]string{"i": "true", "profile": "checksum=0"}},
+		{flags: map[string]string{"batch": "true", "keepgoing": "true", "output": "jsonl"}},
+		{flags: map[string]string{"barcode": "code39", "format": "png"}, args: []string{"12"}},
+		{flags: map[string]string{"output": "json"}, args: []string{"inspect"}},
+		{flags: map[string]string{"id": "true"}, args: []string{"iban", "NL91ABNA0417164300"}},
+		{flags: map[string]string{"verbose": "true"}, args: []string{"iban", "NL", "ABNA0417164300"}},
+		{flags: map[string]string{"groupsize": "5"}, args: []string{"rf", "539007547034"}},
+
+		// Flags without effect, also when they are given with their default value.
+		{flags: map[string]string{"id": "true", "spell": "true"}, args: []string{"CNH"}, wantErr: true},
+		{flags: map[string]string{"spell": "true"}, args: []string{"decode", "CNH"}, wantErr: true},
+		{flags: map[string]string{"output": "json"}, args: []string{"grep"}, wantErr: true},
+		{flags: map[string]string{"output": "text"}, args: []string{"grep"}, wantErr: true},
+		{flags: map[string]string{"profile": "checksum=0"}, args: []string{"12"}, wantErr: true},
+		{flags: map[string]string{"profile": "checksum=0"}, args: []string{"inspect"}, wantErr: true},
+		{flags: map[string]string{"dialect": "en"}, args: []string{"12"}, wantErr: true},
+		{flags: map[string]string{"format": "svg"}, args: []string{"12"}, wantErr: true},
+		{flags: map[string]string{"keepgoing": "true"}, args: []string{"12"}, wantErr: true},
+		{flags: map[string]string{"batch": "true"}, args: []string{"grep"}, wantErr: true},
+		{flags: map[string]string{"emit": "regex", "output": "json"}, wantErr: true},
+		{flags: map[string]string{"emit": "regex"}, args: []string{"inspect"}, wantErr: true},
+		{flags: map[string]string{"spell": "true"}, args: []string{"iban", "NL", "ABNA0417164300"}, wantErr: true},
+		{flags: map[string]string{"id": "true"}, args: []string{"grep"}, wantErr: true},
+
+		// Wrong arguments.
+		{args: []string{"grep", "file.txt"}, wantErr: true},
+		{flags: map[string]string{"emit": "regex"}, args: []string{"12"}, wantErr: true},
+		{args: []string{"inspect", "grep"}, wantErr: true},
+		{flags: map[string]string{"i": "true"}, args: []string{"encode"}, wantErr: true},
+	} {
+		for name, value := range test.flags {
+			if err := flag.Set(name, value); err != nil {
+				t.Fatalf("flag.Set(%q, %q) = %v, want nil error", name, value, err)
+			}
+		}
+		cmd, args := "", test.args
+		if len(args) > 0 && subcommands[args[0]] {
+			cmd, args = args[0], args[1:]
+		}
+		decode := *idFlag || cmd == "decode" || cmd == "validate" || cmd == "normalize"
+		err := checkFlags(cmd, mode(cmd, args), args, decode)
+		if gotErr := err != nil; gotErr != test.wantErr {
+			t.Errorf("checkFlags() for %v with flags %v = %v, want error: %v", test.args, test.flags, err, test.wantErr)
+		}
+		resetFlags()
+	}
+}
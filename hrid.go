@@ -1,14 +1,22 @@
 package main
 
 import (
+	"bufio"
+	"encoding/json"
 	"flag"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/KarelKubat/flagnames"
+	"github.com/KarelKubat/hrid/barcode"
+	"github.com/KarelKubat/hrid/er"
+	"github.com/KarelKubat/hrid/iban"
 	"github.com/KarelKubat/hrid/id"
+	"github.com/KarelKubat/hrid/qr"
 )
 
 const (
@@ -17,8 +25,44 @@ This is hrid, the Human Readable ID converter.
 Usage:
   hrid [FLAGS] NUMBER - generates a human readable ID and prints it on stdout
   hrid [FLAGS] -id ID - re-interprets the ID as a number and prints it on stdout
+  hrid encode [FLAGS] NUMBER
+                      - same as hrid NUMBER
+  hrid decode [FLAGS] ID
+                      - same as hrid -id ID
+  hrid validate [FLAGS] ID
+                      - prints nothing, but exits with 0 when the ID is valid, or with 1 (see below)
+  hrid normalize [FLAGS] ID
+                      - prints the ID as the converter generates it, e.g. uppercased and grouped
+  hrid inspect [FLAGS]
+                      - prints the options, capacity, first and last ID, length and regular expression of the converter
+  hrid [FLAGS] -id -profile P1 -profile P2 ID
+                      - same, but the ID may also stem from a converter that's described by a profile,
+                        e.g.: -profile alphabet=0123456789ABCDEF,length=9,groupsize=4,checksum=0
+  hrid -qr term NUMBER - same, but prints a QR code of the ID too; -qr png or -qr svg write an image to stdout
+  hrid -barcode code128 NUMBER
+                      - same, but writes an SVG barcode to stdout (or code39; -format png for a PNG)
+  hrid -config FILE NUMBER
+                      - same, but the converter is defined by a JSON file; flags that are given still apply
+  hrid [FLAGS] -emit regex|jsonschema
+                      - prints a regular expression or JSON Schema that pre-validates IDs without hrid
+  hrid [FLAGS] grep   - finds IDs in stdin and prints them with their line, column, number and confidence
+  hrid [FLAGS] [-id] -batch
+                      - converts the numbers (or IDs) of stdin, one per line; a failure stops, unless -keepgoing
+  hrid -output json|jsonl [FLAGS] ...
+                      - converts as above, but writes objects with the input, ID, number, checksum and error
+  hrid -i [FLAGS]     - starts an interactive session: numbers and IDs are validated while they are typed, and
+                        invalid IDs are shown with the offending rune and suggestions
+  hrid iban COUNTRY BBAN   - generates an IBAN, e.g.: hrid iban NL ABNA0417164300
+  hrid -id iban IBAN       - validates an IBAN
+  hrid rf REFERENCE        - generates an RF creditor reference
+  hrid -id rf REFERENCE    - validates an RF creditor reference
 
-The flags can be abbreviated: -a for -alphabet, -l for -length etc.
+The exit status is 0 when all inputs are converted, 1 when some are invalid (e.g. a checksum error), 2 for
+wrong flags or arguments (including flags that would have no effect, e.g. -spell with -id), 3 when the converter
+can't be instantiated (e.g. the alphabet is too short), and 4 for other failures (e.g. stdin can't be read).
+
+The flags can be abbreviated: -a for -alphabet, -l for -length etc. The subcommands encode, decode, validate,
+normalize and inspect accept them before and after their name.
 Supported flags:
 `
 )
@@ -29,56 +73,591 @@ var (
 	ignoreCaseFlag = flag.Bool("ignorecase", id.IgnoreCase, "when true, casing is ignored when converting IDs to numbers")
 	groupsizeFlag  = flag.Int("groupsize", id.GroupSize, "size of space-delimited groups in generated IDs, for better readability")
 	checksumFlag   = flag.Int("checksum", id.ChecksumLen, "number of checksum runes to append")
+	templateFlag   = flag.String("template", "", "pattern such as AA-9999-A, overrules alphabet, length, groupsize and checksum")
+	configFlag     = flag.String("config", "", "JSON file that defines the converter, see -verbose for the format")
 
 	idFlag      = flag.Bool("id", false, "when true, arguments are taken as IDs, default: numbers")
 	verboseFlag = flag.Bool("verbose", false, "show options with which the converter is instantiated")
+	spellFlag   = flag.Bool("spell", false, "when true, generated IDs are spelled for reading them aloud")
+	dialectFlag = flag.String("dialect", "en", "language for -spell: en or nl")
+	qrFlag      = flag.String("qr", "", "when set, generated IDs are rendered as a QR code: png, svg or term")
+	barcodeFlag = flag.String("barcode", "", "when set, generated IDs are rendered as a barcode: code128 or code39")
+	formatFlag  = flag.String("format", "svg", "image format for -barcode: svg or png")
+	emitFlag    = flag.String("emit", "", "when set, prints what IDs look like instead of converting: regex or jsonschema")
+	batchFlag   = flag.Bool("batch", false, "when true, numbers (or IDs) are read from stdin, one per line, instead of arguments")
+	keepFlag    = flag.Bool("keepgoing", false, "when true, -batch continues after failures, which yield empty lines")
+	outputFlag  = flag.String("output", "text", "format of conversions: text, json (an array) or jsonl (an object per line)")
+	replFlag    = flag.Bool("i", false, "when true, starts an interactive session that takes numbers and IDs")
+	profileFlag profiles
 )
 
+func init() {
+	flag.Var(&profileFlag, "profile", "additional converter to try with -id, as key=value pairs (may repeat)")
+}
+
+// profiles collects the -profile flags. Each profile is a comma-separated list of key=value pairs, where the keys are
+// the names of the converter flags: alphabet, length, ignorecase, groupsize, checksum or template. The key config
+// loads a JSON file, as -config does; keys that follow it override the file.
+type profiles []string
+
+// String satisfies flag.Value.
+func (p *profiles) String() string {
+	return strings.Join(*p, " ")
+}
+
+// Set satisfies flag.Value.
+func (p *profiles) Set(v string) error {
+	*p = append(*p, v)
+	return nil
+}
+
+// profileOpts converts a profile to converter options. Keys that aren't mentioned get the defaults of package id.
+func profileOpts(profile string) (*id.Opts, error) {
+	opts := &id.Opts{
+		Alphabet:    id.Alphabet,
+		StringLen:   id.StringLen,
+		IgnoreCase:  id.IgnoreCase,
+		GroupSize:   id.GroupSize,
+		ChecksumLen: id.ChecksumLen,
+	}
+	for _, kv := range strings.Split(profile, ",") {
+		parts := strings.SplitN(kv, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("profile %q: %q is not a key=value pair", profile, kv)
+		}
+		var err error
+		switch parts[0] {
+		case "alphabet":
+			opts.Alphabet = parts[1]
+		case "length":
+			opts.StringLen, err = strconv.Atoi(parts[1])
+		case "ignorecase":
+			opts.IgnoreCase, err = strconv.ParseBool(parts[1])
+		case "groupsize":
+			opts.GroupSize, err = strconv.Atoi(parts[1])
+		case "checksum":
+			opts.ChecksumLen, err = strconv.Atoi(parts[1])
+		case "template":
+			opts.Template = parts[1]
+		case "config":
+			loaded, idErr := id.LoadOpts(parts[1])
+			if idErr != nil {
+				return nil, fmt.Errorf("profile %q: %v", profile, idErr)
+			}
+			opts = loaded
+		default:
+			return nil, fmt.Errorf("profile %q: unknown key %q", profile, parts[0])
+		}
+		if err != nil {
+			return nil, fmt.Errorf("profile %q: %v", profile, err)
+		}
+	}
+	return opts, nil
+}
+
 func main() {
 	flag.Usage = func() {
 		fmt.Fprintf(os.Stderr, usage)
 		flag.PrintDefaults()
-		os.Exit(1)
 	}
 	flagnames.Patch()
 	flag.Parse()
-	hrid(flag.Args())
+	os.Exit(hrid(flag.Args()))
+}
+
+// subcommands are the commands that accept flags after their name too.
+var subcommands = map[string]bool{
+	"encode":    true,
+	"decode":    true,
+	"validate":  true,
+	"normalize": true,
+	"inspect":   true,
 }
 
-// hrid is a helper function that can be called from the unit test.
-func hrid(args []string) {
-	if len(args) == 0 {
+// hrid is a helper function that can be called from the unit test. It returns the exit status.
+func hrid(args []string) int {
+	cmd := ""
+	if len(args) > 0 && subcommands[args[0]] {
+		cmd = args[0]
+		if err := flag.CommandLine.Parse(args[1:]); err != nil {
+			return exitUsage
+		}
+		args = flag.Args()
+	}
+	m := mode(cmd, args)
+	if m == modeArgs && len(args) == 0 {
 		flag.Usage()
+		return exitUsage
+	}
+	decode := *idFlag || cmd == "decode" || cmd == "validate" || cmd == "normalize"
+	if err := checkFlags(cmd, m, args, decode); err != nil {
+		log.Print(err)
+		return exitUsage
+	}
+	switch m {
+	case modeIBAN:
+		return ibanCmd(args[1:])
+	case modeRF:
+		return rfCmd(args[1:])
+	}
+	idConverter, opts, status := flagConverter()
+	if status != exitOK {
+		return status
 	}
+	switch m {
+	case modeGrep:
+		return grepCmd(idConverter)
+	case modeInspect:
+		return inspectCmd(idConverter, opts)
+	case modeEmit:
+		return emitCmd(idConverter)
+	}
+	converters, status := profileConverters(idConverter)
+	if status != exitOK {
+		return status
+	}
+	switch m {
+	case modeInteractive:
+		return interactiveCmd(newSession(converters))
+	case modeBatch:
+		return batchCmd(convertFunc(converters, cmd, decode))
+	}
+	return argsCmd(convertFunc(converters, cmd, decode), idConverter, cmd, decode, args)
+}
+
+// flagConverter instantiates the converter of the flags, with -config when given. It returns the converter, its
+// options and the exit status.
+func flagConverter() (*id.ID, *id.Opts, int) {
 	opts := &id.Opts{
 		Alphabet:    *alphabetFlag,
 		StringLen:   *lenFlag,
 		IgnoreCase:  *ignoreCaseFlag,
 		GroupSize:   *groupsizeFlag,
 		ChecksumLen: *checksumFlag,
+		Template:    *templateFlag,
+	}
+	if *configFlag != "" {
+		loaded, err := id.LoadOpts(*configFlag)
+		if err != nil {
+			log.Print(err)
+			return nil, nil, exitCode(err)
+		}
+		flag.Visit(func(f *flag.Flag) {
+			switch f.Name {
+			case "alphabet":
+				loaded.Alphabet = opts.Alphabet
+			case "length":
+				loaded.StringLen = opts.StringLen
+			case "ignorecase":
+				loaded.IgnoreCase = opts.IgnoreCase
+			case "groupsize":
+				loaded.GroupSize = opts.GroupSize
+			case "checksum":
+				loaded.ChecksumLen = opts.ChecksumLen
+			case "template":
+				loaded.Template = opts.Template
+			}
+		})
+		opts = loaded
 	}
 	idConverter, err := id.New(opts)
 	if err != nil {
-		log.Fatal(err)
+		log.Print(err)
+		return nil, nil, exitCode(err)
 	}
 	if *verboseFlag {
-		log.Printf("Converter options: %+v", *opts)
+		log.Printf("Converter options: %v", optsJSON(opts))
+		if t := idConverter.Template(); t != nil {
+			if capacity, ok := t.Capacity(); ok {
+				log.Printf("Template capacity: %v IDs", capacity)
+			} else {
+				log.Printf("Template capacity: exceeds uint64")
+			}
+		}
 	}
-	for _, a := range args {
-		if *idFlag {
-			n, err := idConverter.ToNr(a)
+	return idConverter, opts, exitOK
+}
+
+// profileConverters returns the converter of the flags, followed by those of -profile, and the exit status.
+func profileConverters(idConverter *id.ID) ([]*id.ID, int) {
+	converters := []*id.ID{idConverter}
+	for i, p := range profileFlag {
+		opts, err := profileOpts(p)
+		if err != nil {
+			log.Print(err)
+			return nil, exitConfig
+		}
+		c, idErr := id.New(opts)
+		if idErr != nil {
+			log.Printf("profile %q: %v", p, idErr)
+			return nil, exitCode(idErr)
+		}
+		if *verboseFlag {
+			log.Printf("Converter %v options: %v", i+1, optsJSON(opts))
+		}
+		converters = append(converters, c)
+	}
+	return converters, exitOK
+}
+
+// emitCmd prints what the IDs of the converter look like, as requested by -emit, and returns the exit status.
+func emitCmd(idConverter *id.ID) int {
+	if *emitFlag == "jsonschema" {
+		fmt.Println(idConverter.JSONSchema())
+	} else {
+		fmt.Println(idConverter.Regexp())
+	}
+	return exitOK
+}
+
+// inspectCmd describes the converter in the format of -output, and returns the exit status.
+func inspectCmd(idConverter *id.ID, opts *id.Opts) int {
+	if err := inspect(idConverter, opts).write(os.Stdout, *outputFlag); err != nil {
+		log.Print(err)
+		return exitFailure
+	}
+	return exitOK
+}
+
+// grepCmd finds IDs in stdin, and returns the exit status.
+func grepCmd(idConverter *id.ID) int {
+	if err := grep(idConverter, os.Stdin, os.Stdout); err != nil {
+		log.Print(err)
+		return exitFailure
+	}
+	return exitOK
+}
+
+// batchCmd converts the lines of stdin, and returns the exit status.
+func batchCmd(convert func(string) *result) int {
+	status, err := batch(convert, os.Stdin, os.Stdout, *outputFlag, *keepFlag)
+	if err != nil {
+		log.Print(err)
+	}
+	return status
+}
+
+// convertFunc returns the function that converts an argument or a line of -batch: a number to an ID, or when
+// decode, an ID to a number. The first converter is that of the flags; the others stem from -profile, and are only
+// used for decoding.
+func convertFunc(converters []*id.ID, cmd string, decode bool) func(string) *result {
+	idConverter := converters[0]
+	return func(a string) *result {
+		r := &result{Input: a}
+		if decode {
+			d, err := decodeID(converters, a)
 			if err != nil {
-				log.Printf("%v: not a valid ID: %v", a, err)
+				return r.fail("not a valid ID", err)
+			}
+			switch {
+			case d.Ambiguous:
+				for i, m := range d.Matches {
+					r.Ambiguous = append(r.Ambiguous, match{Converter: m, Nr: strconv.FormatUint(d.Nrs[i], 10)})
+				}
+				if *outputFlag == "text" {
+					log.Printf("%v: ambiguous, accepted by converters %v as numbers %v, using %v", a, d.Matches, d.Nrs,
+						d.Index)
+				}
+			case *verboseFlag:
+				log.Printf("%v: accepted by converter %v", a, d.Index)
+			}
+			c := converters[d.Index]
+			r.Nr = strconv.FormatUint(d.Nr, 10)
+			r.ID = c.ToString(d.Nr)
+			r.Checksum, _ = c.ChecksumRunes(d.Nr)
+			r.text = r.Nr
+			if cmd == "normalize" {
+				r.text = r.ID
+			}
+			return r
+		}
+		u, err := strconv.ParseUint(a, 10, 64)
+		if err != nil {
+			return r.failNumber(err)
+		}
+		s, idErr := idConverter.ToCheckedString(u)
+		if idErr != nil {
+			return r.fail("cannot convert", idErr)
+		}
+		r.Nr = strconv.FormatUint(u, 10)
+		r.ID = s
+		r.Checksum, _ = idConverter.ChecksumRunes(u)
+		r.text = s
+		if *spellFlag {
+			r.Spelling = idConverter.Spell(s, id.Phonetics[*dialectFlag])
+			r.text = r.Spelling
+		}
+		return r
+	}
+}
+
+// argsCmd converts the arguments, or renders the images of generated IDs as requested by -qr or -barcode. It returns
+// the exit status of the first failure.
+func argsCmd(convert func(string) *result, idConverter *id.ID, cmd string, decode bool, args []string) int {
+	status := exitOK
+	out := &output{w: os.Stdout, format: *outputFlag}
+	for _, a := range args {
+		if !decode && (*barcodeFlag != "" || *qrFlag != "") {
+			if s := imageCmd(idConverter, a); status == exitOK {
+				status = s
+			}
+			continue
+		}
+		r := convert(a)
+		if status == exitOK {
+			status = r.status()
+		}
+		if cmd == "validate" {
+			if err := r.err(); err != nil && *verboseFlag {
+				log.Print(err)
+			}
+			continue
+		}
+		if err := r.err(); err != nil && *outputFlag == "text" {
+			log.Print(err)
+			continue
+		}
+		if err := out.write(r); err != nil {
+			log.Print(err)
+			return exitFailure
+		}
+	}
+	if err := out.close(); err != nil {
+		log.Print(err)
+		return exitFailure
+	}
+	return status
+}
+
+// batch converts the lines of r and writes the results to w in the format of -output, in the same order.
+// Surrounding whitespace is ignored. In text, empty lines stay empty; in JSON, they are skipped, and objects hold
+// their line number. A failure stops the conversion, unless keepGoing; then an error with the number of failures is
+// returned at the end. In text, a failure is logged with its line number, and its output is an empty line, so that
+// the lines of r and w still correspond. The output is flushed whenever reading r would block, so that hrid can serve
+// as a co-process. The returned exit status is that of the first failure.
+func batch(convert func(string) *result, r io.Reader, w io.Writer, format string, keepGoing bool) (int, error) {
+	br := bufio.NewReader(r)
+	bw := bufio.NewWriter(w)
+	defer bw.Flush()
+	out := &output{w: bw, format: format}
+	lines, failures, status := 0, 0, exitOK
+	for {
+		if br.Buffered() == 0 {
+			if err := bw.Flush(); err != nil {
+				return exitFailure, err
+			}
+		}
+		text, readErr := br.ReadString('\n')
+		if readErr != nil && readErr != io.EOF {
+			return exitFailure, readErr
+		}
+		if text == "" {
+			break
+		}
+		lines++
+		a := strings.TrimSpace(text)
+		if a == "" {
+			if format == "text" {
+				if _, err := fmt.Fprintln(bw); err != nil {
+					return exitFailure, err
+				}
+			}
+			continue
+		}
+		res := convert(a)
+		res.Line = lines
+		if err := res.err(); err != nil {
+			if !keepGoing {
+				if format != "text" {
+					if err := out.write(res); err != nil {
+						return exitFailure, err
+					}
+					if err := out.close(); err != nil {
+						return exitFailure, err
+					}
+				}
+				if err := bw.Flush(); err != nil {
+					return exitFailure, err
+				}
+				return res.status(), fmt.Errorf("line %v: %v", lines, err)
+			}
+			if format == "text" {
+				log.Printf("line %v: %v", lines, err)
+			}
+			if failures == 0 {
+				status = res.status()
+			}
+			failures++
+		}
+		if err := out.write(res); err != nil {
+			return exitFailure, err
+		}
+	}
+	if err := out.close(); err != nil {
+		return exitFailure, err
+	}
+	if err := bw.Flush(); err != nil {
+		return exitFailure, err
+	}
+	if failures > 0 {
+		return status, fmt.Errorf("%v of %v lines failed", failures, lines)
+	}
+	return exitOK, nil
+}
+
+// decodeID converts an ID to a number. Without -profile, the converter of the flags is used directly, so that its
+// error is reported as is; otherwise, a MultiDecoder tries all converters.
+func decodeID(converters []*id.ID, a string) (*id.Decoded, *er.Err) {
+	if len(converters) > 1 {
+		return id.NewMultiDecoder(converters...).ToNr(a)
+	}
+	n, err := converters[0].ToNr(a)
+	if err != nil {
+		return nil, err
+	}
+	return &id.Decoded{Nr: n, Matches: []int{0}, Nrs: []uint64{n}}, nil
+}
+
+// imageCmd renders the ID of a number as requested by -qr or -barcode, and returns the exit status.
+func imageCmd(idConverter *id.ID, a string) int {
+	u, err := strconv.ParseUint(a, 10, 64)
+	if err != nil {
+		log.Printf("%v: not a valid number: %v", a, err)
+		return exitInvalid
+	}
+	s, idErr := idConverter.ToCheckedString(u)
+	switch {
+	case idErr != nil:
+		log.Printf("%v: cannot convert: %v", a, idErr)
+		return exitCode(idErr)
+	case *barcodeFlag != "":
+		return barcodeCmd(idConverter, u, s)
+	default:
+		return qrCmd(idConverter, u, s)
+	}
+}
+
+// optsJSON returns converter options in the JSON format of -config.
+func optsJSON(opts *id.Opts) string {
+	out, err := json.Marshal(opts)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return string(out)
+}
+
+// grep finds IDs in the lines of r. For each, the line number and byte column (both counting from 1), the ID as
+// it was written, its number and the confidence are written to w.
+func grep(idConverter *id.ID, r io.Reader, w io.Writer) error {
+	br := bufio.NewReader(r)
+	for line := 1; ; line++ {
+		text, err := br.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return err
+		}
+		if text == "" {
+			return nil
+		}
+		for _, m := range idConverter.FindAll(strings.TrimRight(text, "\r\n")) {
+			fmt.Fprintf(w, "%v:%v: %v = %v (confidence %.3f)\n", line, m.Start+1, m.Text, m.Nr, m.Confidence)
+		}
+	}
+}
+
+// qrCmd renders the QR code of a generated ID as requested by -qr, and returns the exit status. On the terminal, the
+// ID is printed too.
+func qrCmd(idConverter *id.ID, n uint64, s string) int {
+	c, err := idConverter.ToQR(n, qr.M)
+	if err != nil {
+		log.Printf("%v: cannot create QR code: %v", s, err)
+		return exitCode(err)
+	}
+	switch *qrFlag {
+	case "png":
+		if err := c.PNG(os.Stdout, 8); err != nil {
+			log.Print(err)
+			return exitFailure
+		}
+	case "svg":
+		fmt.Print(c.SVG(8))
+	default:
+		fmt.Println(s)
+		fmt.Print(c.Terminal())
+	}
+	return exitOK
+}
+
+// barcodeCmd writes the barcode of a generated ID as requested by -barcode and -format, and returns the exit status.
+func barcodeCmd(idConverter *id.ID, n uint64, s string) int {
+	c, err := idConverter.ToBarcode(n, barcode.Symbologies[*barcodeFlag])
+	if err != nil {
+		log.Printf("%v: cannot create barcode: %v", s, err)
+		return exitCode(err)
+	}
+	if *formatFlag == "png" {
+		if err := c.PNG(os.Stdout, 2, 80); err != nil {
+			log.Print(err)
+			return exitFailure
+		}
+		return exitOK
+	}
+	fmt.Print(c.SVG(2, 80))
+	return exitOK
+}
+
+// ibanCmd generates an IBAN from a country code and a BBAN, or with -id, validates IBANs. It returns the exit status.
+func ibanCmd(args []string) int {
+	status := exitOK
+	if *idFlag {
+		for _, a := range args {
+			if err := iban.Validate(a); err != nil {
+				log.Printf("%v: not a valid IBAN: %v", a, err)
+				if status == exitOK {
+					status = exitCode(err)
+				}
 			} else {
-				fmt.Println(n)
+				fmt.Println(iban.Electronic(a))
 			}
-		} else {
-			u, err := strconv.ParseUint(a, 10, 64)
-			if err != nil {
-				log.Printf("%v: not a valid number: %v", a, err)
+		}
+		return status
+	}
+	if len(args) != 2 {
+		flag.Usage()
+		return exitUsage
+	}
+	s, err := iban.New(args[0], args[1])
+	if err != nil {
+		log.Printf("%v %v: cannot generate IBAN: %v", args[0], args[1], err)
+		return exitCode(err)
+	}
+	fmt.Println(iban.Format(s))
+	return exitOK
+}
+
+// rfCmd generates RF creditor references, or with -id, validates them. It returns the exit status.
+func rfCmd(args []string) int {
+	status := exitOK
+	for _, a := range args {
+		if *idFlag {
+			if err := iban.ValidateRF(a); err != nil {
+				log.Printf("%v: not a valid creditor reference: %v", a, err)
+				if status == exitOK {
+					status = exitCode(err)
+				}
 			} else {
-				fmt.Println(idConverter.ToString(u))
+				fmt.Println(iban.Electronic(a))
+			}
+			continue
+		}
+		s, err := iban.NewRF(a)
+		if err != nil {
+			log.Printf("%v: cannot generate creditor reference: %v", a, err)
+			if status == exitOK {
+				status = exitCode(err)
 			}
+		} else {
+			fmt.Println(iban.Format(s))
 		}
 	}
+	return status
 }
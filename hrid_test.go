@@ -1,10 +1,462 @@
 package main
 
-import "testing"
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/KarelKubat/flagnames"
+	"github.com/KarelKubat/hrid/er"
+	"github.com/KarelKubat/hrid/id"
+)
 
 func TestMain(t *testing.T) {
 	// Make sure that main's worker works.
 	// This is similar to the CLI `hrid 12`, which should output something like `00000 00000 00CCR` and we don't care
 	// about the actual output, as long as the main binary works we're fine here. Other tests check the conversions.
-	hrid([]string{"12"})
+	if got := hrid([]string{"12"}); got != exitOK {
+		t.Errorf("hrid(12) = %v, want %v", got, exitOK)
+	}
+}
+
+func TestExitStatus(t *testing.T) {
+	for _, test := range []struct {
+		flags map[string]string
+		args  []string
+		want  int
+	}{
+		{
+			args: []string{"12", "9999999999999999999"},
+			want: exitOK,
+		},
+		{
+			flags: map[string]string{"id": "true"},
+			args:  []string{"cnh m74 xcq y4q h24"},
+			want:  exitOK,
+		},
+		{
+			args: []string{"12", "twelve", "13"},
+			want: exitInvalid,
+		},
+		{
+			flags: map[string]string{"id": "true"},
+			args:  []string{"CNH M74 XCQ Y4Q H25"},
+			want:  exitInvalid,
+		},
+		{
+			flags: map[string]string{"id": "true", "output": "jsonl"},
+			args:  []string{"000 000 000 000 CCR", "C"},
+			want:  exitInvalid,
+		},
+		{
+			flags: map[string]string{"template": "99-#"},
+			args:  []string{"100"},
+			want:  exitInvalid,
+		},
+		{
+			args: []string{"iban", "NL", "ABNA0417164300"},
+			want: exitOK,
+		},
+		{
+			flags: map[string]string{"id": "true"},
+			args:  []string{"iban", "NL00ABNA0417164300"},
+			want:  exitInvalid,
+		},
+		{
+			args: nil,
+			want: exitUsage,
+		},
+		{
+			args: []string{"iban", "NL"},
+			want: exitUsage,
+		},
+		{
+			flags: map[string]string{"emit": "yaml"},
+			want:  exitUsage,
+		},
+		{
+			flags: map[string]string{"output": "yaml"},
+			args:  []string{"12"},
+			want:  exitUsage,
+		},
+		{
+			flags: map[string]string{"i": "true"},
+			args:  []string{"12"},
+			want:  exitUsage,
+		},
+		{
+			flags: map[string]string{"alphabet": "0"},
+			args:  []string{"12"},
+			want:  exitConfig,
+		},
+		{
+			flags: map[string]string{"alphabet": "0120"},
+			args:  []string{"12"},
+			want:  exitConfig,
+		},
+		{
+			flags: map[string]string{"config": "/nonexistent/hrid.json"},
+			args:  []string{"12"},
+			want:  exitConfig,
+		},
+	} {
+		for name, value := range test.flags {
+			if err := flag.Set(name, value); err != nil {
+				t.Fatalf("flag.Set(%q, %q) = %v, want nil error", name, value, err)
+			}
+		}
+		if got := hrid(test.args); got != test.want {
+			t.Errorf("hrid(%v) with flags %v = %v, want %v", test.args, test.flags, got, test.want)
+		}
+		resetFlags()
+	}
+}
+
+func TestSubcommands(t *testing.T) {
+	for _, test := range []struct {
+		flags map[string]string
+		args  []string
+		want  int
+	}{
+		{
+			args: []string{"encode", "12"},
+			want: exitOK,
+		},
+		{
+			args: []string{"encode", "-length", "9", "-spell", "12"},
+			want: exitOK,
+		},
+		{
+			args: []string{"encode", "C"},
+			want: exitInvalid,
+		},
+		{
+			args: []string{"decode", "cnh m74 xcq y4q h24"},
+			want: exitOK,
+		},
+		{
+			args: []string{"decode", "-output", "jsonl", "cnh m74 xcq y4q h24", "12"},
+			want: exitInvalid,
+		},
+		{
+			args: []string{"validate", "CNHM74XCQY4QH24"},
+			want: exitOK,
+		},
+		{
+			args: []string{"validate", "CNH M74 XCQ Y4Q H24", "CNH M74 XCQ Y4Q H25"},
+			want: exitInvalid,
+		},
+		{
+			args: []string{"normalize", "cnhm74xcqy4qh24"},
+			want: exitOK,
+		},
+		{
+			args: []string{"inspect"},
+			want: exitOK,
+		},
+		{
+			args: []string{"inspect", "-template", "AA-9999-#", "-output", "json"},
+			want: exitOK,
+		},
+		{
+			args: []string{"encode"},
+			want: exitUsage,
+		},
+		{
+			flags: map[string]string{"id": "true"},
+			args:  []string{"encode", "12"},
+			want:  exitUsage,
+		},
+		{
+			args: []string{"validate", "-output", "json", "CNH M74 XCQ Y4Q H24"},
+			want: exitUsage,
+		},
+		{
+			args: []string{"validate", "rf", "RF18539007547034"},
+			want: exitUsage,
+		},
+		{
+			args: []string{"decode", "iban", "NL91ABNA0417164300"},
+			want: exitUsage,
+		},
+		{
+			args: []string{"encode", "grep"},
+			want: exitUsage,
+		},
+		{
+			args: []string{"inspect", "12"},
+			want: exitUsage,
+		},
+		{
+			args: []string{"inspect", "-alphabet", "0"},
+			want: exitConfig,
+		},
+	} {
+		for name, value := range test.flags {
+			if err := flag.Set(name, value); err != nil {
+				t.Fatalf("flag.Set(%q, %q) = %v, want nil error", name, value, err)
+			}
+		}
+		if got := hrid(test.args); got != test.want {
+			t.Errorf("hrid(%v) with flags %v = %v, want %v", test.args, test.flags, got, test.want)
+		}
+		resetFlags()
+	}
+}
+
+// resetFlags is a helper that restores the defaults of the flags of hrid that a test has set. The flags are moved to
+// a new flag set, so that flag.Visit no longer visits them.
+func resetFlags() {
+	profileFlag = nil
+	fs := flag.NewFlagSet(flag.CommandLine.Name(), flag.CommandLine.ErrorHandling())
+	flag.VisitAll(func(f *flag.Flag) {
+		if f.Name != "profile" && !strings.HasPrefix(f.Name, "test.") {
+			f.Value.Set(f.DefValue)
+		}
+		fs.Var(f.Value, f.Name, f.Usage)
+	})
+	flag.CommandLine = fs
+}
+
+func TestAbbreviations(t *testing.T) {
+	for _, test := range []struct {
+		flag string
+		want string
+	}{
+		{flag: "-a", want: "-alphabet"},
+		{flag: "-l", want: "-length"},
+		{flag: "-len", want: "-length"},
+		{flag: "-d=nl", want: "-dialect=nl"},
+		{flag: "-sp", want: "-spell"},
+		{flag: "-ch", want: "-checksum"},
+		{flag: "-co", want: "-config"},
+		{flag: "-c", want: "-c"}, // Ambiguous, left to flag.Parse to reject
+	} {
+		args := []string{test.flag}
+		flagnames.PatchFlagSet(flag.CommandLine, &args)
+		if args[0] != test.want {
+			t.Errorf("PatchFlagSet(%q) = %q, want %q", test.flag, args[0], test.want)
+		}
+	}
+}
+
+func TestProfiles(t *testing.T) {
+	for _, test := range []struct {
+		profile      string
+		wantAlphabet string
+		wantLen      int
+		wantErr      bool
+	}{
+		{
+			profile:      "alphabet=0123456789ABCDEF,length=9",
+			wantAlphabet: "0123456789ABCDEF",
+			wantLen:      9,
+		},
+		{
+			profile:      "checksum=0",
+			wantAlphabet: id.Alphabet,
+			wantLen:      id.StringLen,
+		},
+		{
+			profile: "length=nine",
+			wantErr: true,
+		},
+		{
+			profile: "colour=blue",
+			wantErr: true,
+		},
+		{
+			profile: "alphabet",
+			wantErr: true,
+		},
+		{
+			profile: "config=/nonexistent/hrid.json",
+			wantErr: true,
+		},
+	} {
+		opts, err := profileOpts(test.profile)
+		if gotErr := err != nil; gotErr != test.wantErr {
+			t.Errorf("profileOpts(%q) = _,%v, want error: %v", test.profile, err, test.wantErr)
+			continue
+		}
+		if err == nil && (opts.Alphabet != test.wantAlphabet || opts.StringLen != test.wantLen) {
+			t.Errorf("profileOpts(%q) = %+v, want alphabet %q and length %v", test.profile, opts, test.wantAlphabet, test.wantLen)
+		}
+	}
+}
+
+func TestDecodeID(t *testing.T) {
+	c, idErr := id.New(&id.Opts{
+		Alphabet:    id.Alphabet,
+		StringLen:   id.StringLen,
+		IgnoreCase:  id.IgnoreCase,
+		GroupSize:   id.GroupSize,
+		ChecksumLen: id.ChecksumLen,
+	})
+	if idErr != nil {
+		t.Fatalf("id.New() = _,%v, want nil error", idErr)
+	}
+	// Without profiles, the error of the converter is reported as is.
+	if _, err := decodeID([]*id.ID{c}, "CNH M74 XCQ Y4Q H25"); err == nil || err.Code != er.ChecksumError {
+		t.Errorf("decodeID(CNH M74 XCQ Y4Q H25) = _,%v, want ChecksumError", err)
+	}
+	d, err := decodeID([]*id.ID{c}, "000 000 000 000 CCR")
+	if err != nil || d.Nr != 12 || d.Index != 0 || d.Ambiguous {
+		t.Errorf("decodeID(000 000 000 000 CCR) = %+v,%v, want 12 by converter 0", d, err)
+	}
+}
+
+func TestGrep(t *testing.T) {
+	in := "Hello,\nmy ID is cnh m74 xcq y4q h24, not CNH M74 XCQ Y4Q H25.\nOr was it 000 000 000 000 CCR?\n"
+	want := "2:10: cnh m74 xcq y4q h24 = 9999999999999999999 (confidence 0.899)\n" +
+		"3:11: 000 000 000 000 CCR = 12 (confidence 0.999)\n"
+	c, idErr := id.New(&id.Opts{
+		Alphabet:    id.Alphabet,
+		StringLen:   id.StringLen,
+		IgnoreCase:  id.IgnoreCase,
+		GroupSize:   id.GroupSize,
+		ChecksumLen: id.ChecksumLen,
+	})
+	if idErr != nil {
+		t.Fatalf("id.New() = _,%v, want nil error", idErr)
+	}
+	var out bytes.Buffer
+	if err := grep(c, strings.NewReader(in), &out); err != nil {
+		t.Fatalf("grep() = %v, want nil error", err)
+	}
+	if out.String() != want {
+		t.Errorf("grep() wrote %q, want %q", out.String(), want)
+	}
+
+	// Lines may be longer than the 64 KB of a default bufio.Scanner.
+	long := strings.Repeat("x ", 40000) + "000 000 000 000 CCR\n"
+	out.Reset()
+	if err := grep(c, strings.NewReader(long), &out); err != nil {
+		t.Fatalf("grep(long line) = %v, want nil error", err)
+	}
+	if want := "1:80001: 000 000 000 000 CCR = 12 (confidence 0.999)\n"; out.String() != want {
+		t.Errorf("grep(long line) wrote %q, want %q", out.String(), want)
+	}
+}
+
+func TestBatch(t *testing.T) {
+	c, idErr := id.New(&id.Opts{Alphabet: "0123456789ABCDEF", StringLen: 4, ChecksumLen: 1})
+	if idErr != nil {
+		t.Fatalf("id.New() = _,%v, want nil error", idErr)
+	}
+	convert := func(a string) *result {
+		r := &result{Input: a}
+		n, err := c.ToNr(a)
+		if err != nil {
+			return r.fail("not a valid ID", err)
+		}
+		r.Nr = fmt.Sprint(n)
+		r.text = r.Nr
+		return r
+	}
+	for _, test := range []struct {
+		in        string
+		format    string
+		keepGoing bool
+		wantOut   string
+		wantErr   string
+	}{
+		{
+			in:      "0000\r\n  0101 \n\n0FFE",
+			wantOut: "0\n16\n\n255\n",
+		},
+		{
+			in:      "0000\n0FF1\n0101\n",
+			wantOut: "0\n",
+			wantErr: "line 2: ",
+		},
+		{
+			in:        "0000\n0FF1\n0101\n!\n",
+			keepGoing: true,
+			wantOut:   "0\n\n16\n\n",
+			wantErr:   "2 of 4 lines failed",
+		},
+		{
+			in:      "",
+			wantOut: "",
+		},
+		{
+			in:        "0000\n\n0FF1\n",
+			format:    "jsonl",
+			keepGoing: true,
+			wantOut: `{"line":1,"input":"0000","nr":"0"}` + "\n" +
+				`{"line":3,"input":"0FF1","error":{"code":"ChecksumError","message":"checksum error at 1, expected E"}}` + "\n",
+			wantErr: "1 of 3 lines failed",
+		},
+		{
+			in:     "0101\n0FF1\n0000\n",
+			format: "json",
+			wantOut: "[\n  " + `{"line":1,"input":"0101","nr":"16"}` + ",\n  " +
+				`{"line":2,"input":"0FF1","error":{"code":"ChecksumError","message":"checksum error at 1, expected E"}}` +
+				"\n]\n",
+			wantErr: "line 2: ",
+		},
+	} {
+		if test.format == "" {
+			test.format = "text"
+		}
+		wantStatus := exitOK
+		if test.wantErr != "" {
+			wantStatus = exitInvalid
+		}
+		var out bytes.Buffer
+		status, err := batch(convert, strings.NewReader(test.in), &out, test.format, test.keepGoing)
+		gotErr := ""
+		if err != nil {
+			gotErr = err.Error()
+		}
+		if out.String() != test.wantOut || !strings.HasPrefix(gotErr, test.wantErr) || (gotErr == "") != (test.wantErr == "") {
+			t.Errorf("batch(%q, %v) = _,%q,%q, want %q,%q", test.in, test.keepGoing, out.String(), gotErr,
+				test.wantOut, test.wantErr)
+		}
+		if status != wantStatus {
+			t.Errorf("batch(%q, %v) = %v,_, want %v", test.in, test.keepGoing, status, wantStatus)
+		}
+	}
+}
+
+// brokenWriter is a writer whose writes fail, such as a closed stdout.
+type brokenWriter struct{}
+
+func (brokenWriter) Write([]byte) (int, error) {
+	return 0, errors.New("broken pipe")
+}
+
+func TestBatchWriteError(t *testing.T) {
+	c, idErr := id.New(&id.Opts{Alphabet: "0123456789ABCDEF", StringLen: 4, ChecksumLen: 1})
+	if idErr != nil {
+		t.Fatalf("id.New() = _,%v, want nil error", idErr)
+	}
+	convert := func(a string) *result {
+		r := &result{Input: a}
+		if _, err := c.ToNr(a); err != nil {
+			return r.fail("not a valid ID", err)
+		}
+		return r
+	}
+	for _, test := range []struct {
+		in        string
+		format    string
+		keepGoing bool
+	}{
+		{in: "0000\n", format: "text"},
+		{in: "0000\n", format: "json"},
+		{in: "0000\n0FF1\n", format: "text"},
+		{in: "0FF1\n", format: "jsonl"},
+		{in: "0FF1\n", format: "json", keepGoing: true},
+	} {
+		status, err := batch(convert, strings.NewReader(test.in), brokenWriter{}, test.format, test.keepGoing)
+		if status != exitFailure || err == nil {
+			t.Errorf("batch(%q, %v, %v) to a broken writer = %v,%v, want %v,error", test.in, test.format,
+				test.keepGoing, status, err, exitFailure)
+		}
+	}
 }
@@ -0,0 +1,189 @@
+// Package iban generates and validates IBANs (international bank account numbers, ISO 13616) and RF creditor
+// references (ISO 11649). Both use the check digits of ISO 7064 MOD 97-10, computed over a number in which each
+// letter stands for two digits: A=10, B=11, ..., Z=35.
+package iban
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/KarelKubat/hrid/conv"
+	"github.com/KarelKubat/hrid/er"
+)
+
+const (
+	// Alphabet holds the runes that may occur in an IBAN or RF reference. Their index is their value in MOD 97-10.
+	Alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	// GroupSize is the length of groups in the print format of IBANs and RF references.
+	GroupSize = 4
+	// RFPrefix starts each RF creditor reference.
+	RFPrefix = "RF"
+	// RFMaxLen is the maximum length of the reference in an RF creditor reference, without prefix and check digits.
+	RFMaxLen = 21
+)
+
+// BBANLen maps country codes to the length of their BBAN (basic bank account number), the part of an IBAN that
+// follows the country code and the check digits.
+var BBANLen = map[string]int{
+	"AD": 20, "AE": 19, "AL": 24, "AT": 16, "AZ": 24, "BA": 16, "BE": 12, "BG": 18,
+	"BH": 18, "BR": 25, "BY": 24, "CH": 17, "CR": 18, "CY": 24, "CZ": 20, "DE": 18,
+	"DK": 14, "DO": 24, "EE": 16, "EG": 25, "ES": 20, "FI": 14, "FO": 14, "FR": 23,
+	"GB": 18, "GE": 18, "GI": 19, "GL": 14, "GR": 23, "GT": 24, "HR": 17, "HU": 24,
+	"IE": 18, "IL": 19, "IQ": 19, "IS": 22, "IT": 23, "JO": 26, "KW": 26, "KZ": 16,
+	"LB": 24, "LC": 28, "LI": 17, "LT": 16, "LU": 16, "LV": 17, "MC": 23, "MD": 20,
+	"ME": 18, "MK": 15, "MR": 23, "MT": 27, "MU": 26, "NL": 14, "NO": 11, "PK": 20,
+	"PL": 24, "PS": 25, "PT": 21, "QA": 25, "RO": 20, "RS": 18, "SA": 20, "SC": 27,
+	"SE": 20, "SI": 15, "SK": 20, "SM": 23, "ST": 21, "SV": 24, "TL": 19, "TN": 20,
+	"TR": 22, "UA": 25, "VA": 18, "VG": 20, "XK": 16,
+}
+
+var converter *conv.Conv
+
+func init() {
+	var err *er.Err
+	converter, err = conv.New(Alphabet, 0)
+	if err != nil {
+		panic("failed to construct IBAN converter")
+	}
+}
+
+// Electronic returns the electronic format of an IBAN or RF reference: without spaces and in uppercase.
+func Electronic(s string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(s), ""))
+}
+
+// Format returns the print format of an IBAN or RF reference: in uppercase and split into groups of four.
+func Format(s string) string {
+	runes := []rune(Electronic(s))
+	groups := []string{}
+	for i := 0; i < len(runes); i += GroupSize {
+		end := i + GroupSize
+		if end > len(runes) {
+			end = len(runes)
+		}
+		groups = append(groups, string(runes[i:end]))
+	}
+	return strings.Join(groups, " ")
+}
+
+// New returns the IBAN for a country code and a BBAN, in electronic format.
+func New(country, bban string) (string, *er.Err) {
+	country = Electronic(country)
+	bban = Electronic(bban)
+	if err := checkBBAN(country, bban); err != nil {
+		return "", err
+	}
+	check, err := checkDigits(bban + country)
+	if err != nil {
+		return "", err
+	}
+	return country + check + bban, nil
+}
+
+// Validate returns an error when s isn't a valid IBAN. Spaces and casing are ignored.
+func Validate(s string) *er.Err {
+	s = Electronic(s)
+	if len(s) < 5 {
+		return er.Newf(er.IDTooShortError, "IBAN %q needs a country code, check digits and a BBAN", s)
+	}
+	country, check, bban := s[:2], s[2:4], s[4:]
+	if err := checkBBAN(country, bban); err != nil {
+		return err
+	}
+	return verify(bban+country, check)
+}
+
+// NewRF returns the RF creditor reference for a reference, in electronic format.
+func NewRF(ref string) (string, *er.Err) {
+	ref = Electronic(ref)
+	if err := checkRef(ref); err != nil {
+		return "", err
+	}
+	check, err := checkDigits(ref + RFPrefix)
+	if err != nil {
+		return "", err
+	}
+	return RFPrefix + check + ref, nil
+}
+
+// ValidateRF returns an error when s isn't a valid RF creditor reference. Spaces and casing are ignored.
+func ValidateRF(s string) *er.Err {
+	s = Electronic(s)
+	if !strings.HasPrefix(s, RFPrefix) {
+		return er.Newf(er.PrefixError, "creditor reference %q doesn't start with %v", s, RFPrefix)
+	}
+	if len(s) < len(RFPrefix)+2 {
+		return er.Newf(er.IDTooShortError, "creditor reference %q lacks check digits", s)
+	}
+	check, ref := s[2:4], s[4:]
+	if err := checkRef(ref); err != nil {
+		return err
+	}
+	return verify(ref+RFPrefix, check)
+}
+
+// checkBBAN is a helper to verify the country code and the length of the BBAN.
+func checkBBAN(country, bban string) *er.Err {
+	want, ok := BBANLen[country]
+	if !ok {
+		return er.Newf(er.UnknownCountryError, "country code %q has no IBAN format", country)
+	}
+	if got := len([]rune(bban)); got != want {
+		return er.Newf(er.BBANLengthError, "BBAN %q for country %v has length %v, want %v", bban, country, got, want)
+	}
+	return nil
+}
+
+// checkRef is a helper to verify the length of the reference part of an RF creditor reference.
+func checkRef(ref string) *er.Err {
+	if got := len([]rune(ref)); got < 1 || got > RFMaxLen {
+		return er.Newf(er.ReferenceLengthError, "reference %q has length %v, want 1 to %v", ref, got, RFMaxLen)
+	}
+	return nil
+}
+
+// checkDigits is a helper that computes the two check digits for s, which is the payload followed by the prefix.
+func checkDigits(s string) (string, *er.Err) {
+	rem, err := mod97(s + "00")
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%02d", 98-rem), nil
+}
+
+// verify is a helper that checks the check digits for s, which is the payload followed by the prefix.
+func verify(s, check string) *er.Err {
+	if len(check) != 2 || check[0] < '0' || check[0] > '9' || check[1] < '0' || check[1] > '9' {
+		return er.Newf(er.ChecksumError, "check digits %q must be two digits", check)
+	}
+	if check < "02" || check > "98" {
+		return er.Newf(er.ChecksumError, "check digits %q must be in the range 02-98", check)
+	}
+	rem, err := mod97(s + check)
+	if err != nil {
+		return err
+	}
+	if rem != 1 {
+		want, _ := checkDigits(s)
+		return er.Newf(er.ChecksumError, "checksum error at %v, expected %v", check, want)
+	}
+	return nil
+}
+
+// mod97 is a helper that computes the remainder of ISO 7064 MOD 97-10, where each rune of s is taken as one digit (0-9)
+// or two digits (A-Z, 10-35). The computation is piecewise, since the number can be way larger than a uint64.
+func mod97(s string) (int, *er.Err) {
+	rem := 0
+	for _, r := range s {
+		v, err := converter.Index(r)
+		if err != nil {
+			return 0, err
+		}
+		if v < 10 {
+			rem = (rem*10 + v) % 97
+		} else {
+			rem = (rem*100 + v) % 97
+		}
+	}
+	return rem, nil
+}
@@ -0,0 +1,103 @@
+package iban
+
+import (
+	"testing"
+
+	"github.com/KarelKubat/hrid/er"
+)
+
+func TestNew(t *testing.T) {
+	for _, test := range []struct {
+		country, bban string
+		wantIBAN      string
+		wantCode      er.Code
+	}{
+		{country: "GB", bban: "WEST12345698765432", wantIBAN: "GB82WEST12345698765432"},
+		{country: "de", bban: "3704 0044 0532 0130 00", wantIBAN: "DE89370400440532013000"},
+		{country: "NL", bban: "ABNA0417164300", wantIBAN: "NL91ABNA0417164300"},
+		{country: "BE", bban: "539007547034", wantIBAN: "BE68539007547034"},
+		{country: "FR", bban: "20041010050500013M02606", wantIBAN: "FR1420041010050500013M02606"},
+		{country: "ZZ", bban: "539007547034", wantCode: er.UnknownCountryError},
+		{country: "BE", bban: "53900754703", wantCode: er.BBANLengthError},
+		{country: "BE", bban: "5390075470-4", wantCode: er.NoSuchTokenError},
+	} {
+		gotIBAN, err := New(test.country, test.bban)
+		gotCode := er.None
+		if err != nil {
+			gotCode = err.Code
+		}
+		if gotIBAN != test.wantIBAN || gotCode != test.wantCode {
+			t.Errorf("New(%q,%q) = %q,%v, want %q,%v", test.country, test.bban, gotIBAN, err, test.wantIBAN, test.wantCode)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for _, test := range []struct {
+		iban     string
+		wantCode er.Code
+	}{
+		{iban: "GB82 WEST 1234 5698 7654 32"},
+		{iban: "gb82west12345698765432"},
+		{iban: "GB28 WEST 1234 5698 7654 32", wantCode: er.ChecksumError},
+		{iban: "GB82 WEST 1234 5698 7654 23", wantCode: er.ChecksumError},
+		{iban: "GB00 WEST 1234 5698 7654 32", wantCode: er.ChecksumError},
+		{iban: "NL0SABNA0417164300", wantCode: er.ChecksumError},
+		{iban: "NL1PABNA0417164300", wantCode: er.ChecksumError},
+		{iban: "NL2MABNA0417164300", wantCode: er.ChecksumError},
+		{iban: "NL3JABNA0417164300", wantCode: er.ChecksumError},
+		{iban: "NL4GABNA0417164300", wantCode: er.ChecksumError},
+		{iban: "NL5DABNA0417164300", wantCode: er.ChecksumError},
+		{iban: "NL6AABNA0417164300", wantCode: er.ChecksumError},
+		{iban: "GB82 WEST 1234 5698 7654", wantCode: er.BBANLengthError},
+		{iban: "XX82 WEST 1234 5698 7654 32", wantCode: er.UnknownCountryError},
+		{iban: "GB82", wantCode: er.IDTooShortError},
+	} {
+		err := Validate(test.iban)
+		gotCode := er.None
+		if err != nil {
+			gotCode = err.Code
+		}
+		if gotCode != test.wantCode {
+			t.Errorf("Validate(%q) = %v, want %v", test.iban, err, test.wantCode)
+		}
+	}
+}
+
+func TestRF(t *testing.T) {
+	rf, err := NewRF("5390 0754 7034")
+	if err != nil || rf != "RF18539007547034" {
+		t.Errorf("NewRF(539007547034) = %q,%v, want RF18539007547034,nil", rf, err)
+	}
+	if _, err := NewRF("1234567890123456789012"); err == nil || err.Code != er.ReferenceLengthError {
+		t.Errorf("NewRF() with a 22-rune reference = _,%v, want ReferenceLengthError", err)
+	}
+
+	for _, test := range []struct {
+		rf       string
+		wantCode er.Code
+	}{
+		{rf: "RF18 5390 0754 7034"},
+		{rf: "rf18539007547034"},
+		{rf: "RF81 5390 0754 7034", wantCode: er.ChecksumError},
+		{rf: "RF1I 5390 0754 7034", wantCode: er.ChecksumError},
+		{rf: "XX18 5390 0754 7034", wantCode: er.PrefixError},
+		{rf: "RF18", wantCode: er.ReferenceLengthError},
+		{rf: "RF", wantCode: er.IDTooShortError},
+	} {
+		err := ValidateRF(test.rf)
+		gotCode := er.None
+		if err != nil {
+			gotCode = err.Code
+		}
+		if gotCode != test.wantCode {
+			t.Errorf("ValidateRF(%q) = %v, want %v", test.rf, err, test.wantCode)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	if got := Format("gb82west12345698765432"); got != "GB82 WEST 1234 5698 7654 32" {
+		t.Errorf("Format() = %q, want GB82 WEST 1234 5698 7654 32", got)
+	}
+}
@@ -0,0 +1,43 @@
+package id
+
+import (
+	"strings"
+
+	"github.com/KarelKubat/hrid/barcode"
+	"github.com/KarelKubat/hrid/er"
+)
+
+// ToBarcode converts a uint64 to a 1D barcode that holds the ID as ToString generates it, but without the spaces
+// between groups. Template-driven IDs are encoded as-is. An error occurs when any rune that an ID of this converter
+// may hold, not just the ones of this ID, can't be represented in the symbology; so that a configuration that works
+// for one ID works for all.
+func (id *ID) ToBarcode(n uint64, s barcode.Symbology) (*barcode.Code, *er.Err) {
+	if err := s.Check(id.runes()); err != nil {
+		return nil, err
+	}
+	runes, err := id.toRunes(n)
+	if err != nil {
+		return nil, err
+	}
+	out := string(runes)
+	if id.template == nil {
+		out = strings.ReplaceAll(out, " ", "")
+	}
+	return barcode.Encode(s, out)
+}
+
+// runes is a helper that returns all runes that a generated ID may hold, except for spaces between groups.
+func (id *ID) runes() string {
+	var out strings.Builder
+	if id.opts.Version != 0 {
+		out.WriteRune(id.opts.Version)
+	}
+	if id.template == nil {
+		out.WriteString(id.converter.Alphabet())
+		return out.String()
+	}
+	for i := 0; i < id.template.Len(); i++ {
+		out.WriteString(id.template.Alphabet(i))
+	}
+	return out.String()
+}
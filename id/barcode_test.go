@@ -0,0 +1,64 @@
+package id
+
+import (
+	"testing"
+
+	"github.com/KarelKubat/hrid/barcode"
+	"github.com/KarelKubat/hrid/er"
+)
+
+func TestToBarcode(t *testing.T) {
+	lower, err := New(&Opts{Alphabet: "0123456789abcdef", ChecksumLen: 1})
+	if err != nil {
+		t.Fatalf("New(0-f) returned unexpected error %v", err)
+	}
+	tmpl, err := New(&Opts{Template: "AA_99"})
+	if err != nil {
+		t.Fatalf("New(AA_99) returned unexpected error %v", err)
+	}
+	for _, test := range []struct {
+		id        *ID
+		symbology barcode.Symbology
+		wantText  string
+		wantCode  er.Code
+	}{
+		{
+			id:        converter,
+			symbology: barcode.Code39,
+			wantText:  "000000000000CCR",
+		},
+		{
+			id:        lower,
+			symbology: barcode.Code128,
+			wantText:  "cc",
+		},
+		{
+			id:        lower,
+			symbology: barcode.Code39,
+			wantCode:  er.UnrepresentableError,
+		},
+		{
+			id:        tmpl,
+			symbology: barcode.Code128,
+			wantText:  "AA_12",
+		},
+		{
+			id:        tmpl,
+			symbology: barcode.Code39,
+			wantCode:  er.UnrepresentableError, // Code 39 lacks the underscore
+		},
+	} {
+		c, err := test.id.ToBarcode(12, test.symbology)
+		gotCode := er.None
+		if err != nil {
+			gotCode = err.Code
+		}
+		if gotCode != test.wantCode {
+			t.Errorf("ToBarcode(12,%v) = _,%v, want error code %v", test.symbology, err, test.wantCode)
+			continue
+		}
+		if err == nil && c.Text != test.wantText {
+			t.Errorf("ToBarcode(12,%v) encodes %q, want %q", test.symbology, c.Text, test.wantText)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package id
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"unicode/utf8"
+
+	"github.com/KarelKubat/hrid/block"
+	"github.com/KarelKubat/hrid/er"
+)
+
+const (
+	// ConfigSchema is the schema version of the JSON form of Opts. It is written by MarshalJSON, and UnmarshalJSON
+	// rejects other versions.
+	ConfigSchema = 1
+)
+
+// config is the JSON form of Opts. The keys match the flags of the hrid command.
+type config struct {
+	Schema     int          `json:"schema"`
+	Alphabet   string       `json:"alphabet,omitempty"`
+	Length     int          `json:"length"`
+	IgnoreCase bool         `json:"ignorecase"`
+	GroupSize  int          `json:"groupsize"`
+	Checksum   int          `json:"checksum"`
+	Template   string       `json:"template,omitempty"`
+	Version    string       `json:"version,omitempty"`
+	Blocklist  *blocklistJS `json:"blocklist,omitempty"`
+}
+
+// blocklistJS is the JSON form of a block.List.
+type blocklistJS struct {
+	Words  []string `json:"words"`
+	MaxRun int      `json:"maxrun"`
+}
+
+// MarshalJSON satisfies json.Marshaler. The result includes the schema version, see ConfigSchema.
+func (o Opts) MarshalJSON() ([]byte, error) {
+	c := config{
+		Schema:     ConfigSchema,
+		Alphabet:   o.Alphabet,
+		Length:     o.StringLen,
+		IgnoreCase: o.IgnoreCase,
+		GroupSize:  o.GroupSize,
+		Checksum:   o.ChecksumLen,
+		Template:   o.Template,
+	}
+	if o.Version != 0 {
+		c.Version = string(o.Version)
+	}
+	if o.Blocklist != nil {
+		c.Blocklist = &blocklistJS{
+			Words:  o.Blocklist.Words,
+			MaxRun: o.Blocklist.MaxRun,
+		}
+	}
+	return json.Marshal(c)
+}
+
+// UnmarshalJSON satisfies json.Unmarshaler. Unknown keys, a schema version other than ConfigSchema, negative lengths
+// and a version of more than one rune are rejected, as an *er.Err with code ConfigError. Whether the options make a
+// working converter is checked by New.
+func (o *Opts) UnmarshalJSON(data []byte) error {
+	if err := o.fromJSON(data); err != nil {
+		return err
+	}
+	return nil
+}
+
+// fromJSON is a helper that implements UnmarshalJSON.
+func (o *Opts) fromJSON(data []byte) *er.Err {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	c := config{}
+	if err := dec.Decode(&c); err != nil {
+		return er.Newf(er.ConfigError, "malformed configuration: %v", err)
+	}
+	if c.Schema != ConfigSchema {
+		return er.Newf(er.ConfigError, "configuration has schema %v, want %v", c.Schema, ConfigSchema)
+	}
+	if c.Length < 0 || c.GroupSize < 0 || c.Checksum < 0 {
+		return er.New(er.ConfigError, "length, groupsize and checksum may not be negative")
+	}
+	if utf8.RuneCountInString(c.Version) > 1 {
+		return er.Newf(er.ConfigError, "version %q must be a single rune", c.Version)
+	}
+	*o = Opts{
+		Alphabet:    c.Alphabet,
+		StringLen:   c.Length,
+		IgnoreCase:  c.IgnoreCase,
+		GroupSize:   c.GroupSize,
+		ChecksumLen: c.Checksum,
+		Template:    c.Template,
+	}
+	if c.Version != "" {
+		o.Version, _ = utf8.DecodeRuneInString(c.Version)
+	}
+	if c.Blocklist != nil {
+		o.Blocklist = &block.List{
+			Words:  c.Blocklist.Words,
+			MaxRun: c.Blocklist.MaxRun,
+		}
+	}
+	return nil
+}
+
+// LoadOpts reads Opts from a JSON file, as written by MarshalJSON.
+func LoadOpts(path string) (*Opts, *er.Err) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, er.Newf(er.ConfigError, "cannot read configuration: %v", err)
+	}
+	o := &Opts{}
+	if err := o.fromJSON(data); err != nil {
+		return nil, er.Newf(er.ConfigError, "%v: %v", path, err.Msg)
+	}
+	return o, nil
+}
+
+// NewFromFile instantiates a converter from a JSON file, see LoadOpts.
+func NewFromFile(path string) (*ID, *er.Err) {
+	o, err := LoadOpts(path)
+	if err != nil {
+		return nil, err
+	}
+	return New(o)
+}
@@ -0,0 +1,79 @@
+package id
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/KarelKubat/hrid/block"
+	"github.com/KarelKubat/hrid/er"
+)
+
+func TestOptsJSON(t *testing.T) {
+	for _, o := range []*Opts{
+		{Alphabet: Alphabet, StringLen: StringLen, IgnoreCase: IgnoreCase, GroupSize: GroupSize, ChecksumLen: ChecksumLen},
+		{Template: "AA-9999-#", Version: 'ß'},
+		{Alphabet: "01", Blocklist: &block.List{Words: []string{"111"}, MaxRun: 2}},
+	} {
+		data, err := json.Marshal(o)
+		if err != nil {
+			t.Fatalf("json.Marshal(%+v) returned unexpected error %v", o, err)
+		}
+		got := &Opts{}
+		if err := json.Unmarshal(data, got); err != nil {
+			t.Fatalf("json.Unmarshal(%s) returned unexpected error %v", data, err)
+		}
+		if !reflect.DeepEqual(got, o) {
+			t.Errorf("json.Unmarshal(%s) = %+v, want %+v", data, got, o)
+		}
+	}
+
+	for _, data := range []string{
+		`{"alphabet": "01"}`,
+		`{"schema": 2, "alphabet": "01"}`,
+		`{"schema": 1, "alphabet": "01", "colour": "blue"}`,
+		`{"schema": 1, "alphabet": "01", "checksum": -1}`,
+		`{"schema": 1, "alphabet": "01", "version": "V2"}`,
+		`{"schema": 1, "alphabet": 1}`,
+	} {
+		err := json.Unmarshal([]byte(data), &Opts{})
+		if e, ok := err.(*er.Err); !ok || e.Code != er.ConfigError {
+			t.Errorf("json.Unmarshal(%s) = %v, want ConfigError", data, err)
+		}
+	}
+}
+
+func TestNewFromFile(t *testing.T) {
+	dir := t.TempDir()
+	good := filepath.Join(dir, "good.json")
+	if err := os.WriteFile(good, []byte(`{"schema": 1, "alphabet": "0123456789", "checksum": 1}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	bad := filepath.Join(dir, "bad.json")
+	if err := os.WriteFile(bad, []byte(`{"schema": 1, "alphabet": "0"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		path     string
+		wantCode er.Code
+	}{
+		{path: good},
+		{path: bad, wantCode: er.AlphabetTooShortError},
+		{path: filepath.Join(dir, "missing.json"), wantCode: er.ConfigError},
+	} {
+		c, err := NewFromFile(test.path)
+		gotCode := er.None
+		if err != nil {
+			gotCode = err.Code
+		}
+		if gotCode != test.wantCode {
+			t.Errorf("NewFromFile(%q) = _,%v, want error code %v", test.path, err, test.wantCode)
+		}
+		if err == nil && c.ToString(12) != "123" {
+			t.Errorf("NewFromFile(%q).ToString(12) = %q, want \"123\"", test.path, c.ToString(12))
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package id
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// regexpSyntax holds the runes that must be escaped in a regular expression. Other punctuation may not be escaped in
+// ECMAScript's unicode mode.
+const regexpSyntax = `^$\.*+?()[]{}|/-`
+
+// Regexp returns a regular expression that matches the shape of the IDs that ToNr accepts: the runes of the alphabet
+// (in both cases, when casing is ignored), whitespace where ToNr skips it, and the number of runes. The checksum isn't
+// verified, so a match only pre-validates an ID. The expression is anchored, and uses only syntax that RE2, PCRE and
+// ECMAScript share: no flags such as (?i), so that it can be used as a JSON Schema pattern.
+//
+// For converters without a template, the number of runes is at least 1 plus the number of checksum runes. There is no
+// upper bound, since ToNr accepts IDs that are padded with any number of zero tokens.
+func (id *ID) Regexp() string {
+	var b strings.Builder
+	b.WriteString("^")
+	if id.opts.Version != 0 {
+		b.WriteString(`\s*`)
+		b.WriteString(id.regexpClass(string(id.opts.Version)))
+	}
+	if id.template != nil {
+		withLiterals, withoutLiterals := "", ""
+		for i := 0; i < id.template.Len(); i++ {
+			class := id.regexpClass(id.template.Alphabet(i))
+			withLiterals += class
+			if _, ok := id.template.Literal(i); !ok {
+				withoutLiterals += class
+			}
+		}
+		if withLiterals == withoutLiterals {
+			fmt.Fprintf(&b, `\s*%v\s*$`, withLiterals)
+		} else {
+			fmt.Fprintf(&b, `\s*(?:%v|%v)\s*$`, withLiterals, withoutLiterals)
+		}
+		return b.String()
+	}
+
+	minLen := id.opts.ChecksumLen + 1
+	class := id.regexpClass(id.converter.Alphabet())
+	if id.opts.GroupSize > 0 {
+		fmt.Fprintf(&b, `\s*(?:%v\s*){%d,}$`, class, minLen)
+	} else {
+		fmt.Fprintf(&b, `%v{%d,}$`, class, minLen)
+	}
+	return b.String()
+}
+
+// JSONSchema returns a JSON Schema (which is also an OpenAPI schema object) for a string that has the shape of an ID,
+// using the pattern of Regexp.
+func (id *ID) JSONSchema() string {
+	schema := struct {
+		Type        string `json:"type"`
+		Pattern     string `json:"pattern"`
+		Description string `json:"description"`
+	}{
+		Type:        "string",
+		Pattern:     id.Regexp(),
+		Description: "Human readable ID; the checksum is not verified by this pattern.",
+	}
+	out, _ := json.MarshalIndent(schema, "", "  ")
+	return string(out)
+}
+
+// regexpClass is a helper that returns a character class for runes, or a single escaped rune. When casing is
+// ignored, the lowercase variants are added.
+func (id *ID) regexpClass(runes string) string {
+	seen := map[rune]bool{}
+	class := []rune{}
+	for _, r := range runes {
+		for _, variant := range []rune{r, unicode.ToLower(r)} {
+			if !seen[variant] && (variant == r || id.opts.IgnoreCase) {
+				seen[variant] = true
+				class = append(class, variant)
+			}
+		}
+	}
+	if len(class) == 1 {
+		return regexpRune(class[0], false)
+	}
+	var b strings.Builder
+	b.WriteString("[")
+	for _, r := range class {
+		b.WriteString(regexpRune(r, true))
+	}
+	b.WriteString("]")
+	return b.String()
+}
+
+// regexpRune is a helper that escapes a rune for a regular expression. A dash is only special in a character class,
+// and ECMAScript's unicode mode doesn't allow escaping it elsewhere.
+func regexpRune(r rune, inClass bool) string {
+	switch {
+	case r == '-' && !inClass:
+		return "-"
+	case strings.ContainsRune(regexpSyntax, r):
+		return `\` + string(r)
+	case r <= ' ' || r == unicode.MaxASCII:
+		return fmt.Sprintf(`\x%02X`, r)
+	default:
+		return string(r)
+	}
+}
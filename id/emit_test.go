@@ -0,0 +1,89 @@
+package id
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestRegexp(t *testing.T) {
+	for _, test := range []struct {
+		opts      *Opts
+		wantMatch []string
+		wantNo    []string
+		padded    []string // Zero-padded IDs, longer than ToString generates, which ToNr accepts
+	}{
+		{
+			opts:      &Opts{Alphabet: Alphabet, StringLen: StringLen, IgnoreCase: true, GroupSize: 3, ChecksumLen: 2},
+			wantMatch: []string{"CNH M74 XCQ Y4Q H24", "cnhm74 xcqy4qh24", "  CCR ", "000 000 000 000 CCR"},
+			wantNo:    []string{"CNH M74 XCQ Y4Q H2I", "CC"},
+			padded:    []string{"000 000 000 000 000 000 CCR", "000000000000000000000CCR", "000 CNH M74 XCQ Y4Q H24"},
+		},
+		{
+			opts:      &Opts{Alphabet: "0123456789abcdef", ChecksumLen: 1},
+			wantMatch: []string{"cc", "deadbeef0"},
+			wantNo:    []string{"CC", "dead beef0", "c"},
+			padded:    []string{"00000000000000000000cc"},
+		},
+		{
+			opts:      &Opts{Alphabet: "01-.", GroupSize: 2, Version: 'v'},
+			wantMatch: []string{"v 1-.0", " v1-.0"},
+			wantNo:    []string{"V 1-.0", "1-.0", "v 1-.2"},
+			padded:    []string{"v 00 00 00 00 00 00 00 00 00 00 00 00 00 00 00 00 00 00 00 00 00 00 00 00 00 00 00 00 00 00 00 00 1-"},
+		},
+		{
+			opts:      &Opts{Template: "AA-99#", IgnoreCase: true},
+			wantMatch: []string{"AB-12X", "ab12x", " AB-12X "},
+			wantNo:    []string{"AB-1X", "AB_12X", "AB-12-X", "AI-12X"},
+		},
+	} {
+		c, err := New(test.opts)
+		if err != nil {
+			t.Fatalf("New(%+v) returned unexpected error %v", test.opts, err)
+		}
+		re, reErr := regexp.Compile(c.Regexp())
+		if reErr != nil {
+			t.Fatalf("Regexp() = %q, which doesn't compile: %v", c.Regexp(), reErr)
+		}
+		for _, n := range []uint64{0, 12, 9999999999999999999, 18446744073709551615} {
+			if s, err := c.ToCheckedString(n); err == nil && !re.MatchString(s) {
+				t.Errorf("Regexp() = %q doesn't match ToString(%v) = %q", c.Regexp(), n, s)
+			}
+		}
+		for _, s := range test.wantMatch {
+			if !re.MatchString(s) {
+				t.Errorf("Regexp() = %q doesn't match %q", c.Regexp(), s)
+			}
+		}
+		for _, s := range test.padded {
+			if _, err := c.ToNr(s); err != nil {
+				t.Errorf("ToNr(%q) = _,%v, need nil error", s, err)
+			}
+			if !re.MatchString(s) {
+				t.Errorf("Regexp() = %q doesn't match %q, which ToNr accepts", c.Regexp(), s)
+			}
+		}
+		for _, s := range test.wantNo {
+			if re.MatchString(s) {
+				t.Errorf("Regexp() = %q matches %q", c.Regexp(), s)
+			}
+			if _, err := c.ToNr(s); err == nil {
+				t.Errorf("ToNr(%q) = _,nil, but Regexp() = %q rejects it", s, c.Regexp())
+			}
+		}
+	}
+}
+
+func TestJSONSchema(t *testing.T) {
+	var schema map[string]string
+	if err := json.Unmarshal([]byte(converter.JSONSchema()), &schema); err != nil {
+		t.Fatalf("JSONSchema() = %q, which doesn't unmarshal: %v", converter.JSONSchema(), err)
+	}
+	if schema["type"] != "string" || schema["pattern"] != converter.Regexp() {
+		t.Errorf("JSONSchema() = %q, want type string and pattern %q", converter.JSONSchema(), converter.Regexp())
+	}
+	if strings.Contains(schema["pattern"], "(?i)") {
+		t.Errorf("JSONSchema() pattern %q uses (?i), which ECMAScript lacks", schema["pattern"])
+	}
+}
@@ -0,0 +1,156 @@
+package id
+
+import (
+	"math"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// UncheckedConfidence is the confidence of a match when the converter has no checksum: then any run of tokens of
+	// the right length is a valid ID.
+	UncheckedConfidence = 0.5
+	// CaseFactor scales the confidence of a match that only differs in casing from what ToString generates.
+	CaseFactor = 0.9
+	// SpacingFactor scales the confidence of a match whose spacing (or for templates, literals) differs from what
+	// ToString generates.
+	SpacingFactor = 0.8
+)
+
+// Match is an ID that FindAll found in a text.
+type Match struct {
+	Start, End int     // Byte offsets of the ID in the text, so that text[Start:End] is the ID.
+	Text       string  // The ID as it occurs in the text.
+	Nr         uint64  // The number that the ID represents.
+	Confidence float64 // How likely it is that the match is an ID, and not a word that happens to pass ToNr.
+}
+
+// FindAll finds all IDs in a free text, such as an email. Candidates are runs of groups of ID runes, where the groups
+// are separated by spaces or tabs, so that odd spacing such as "CNH M74XCQ Y4QH24" is found. Each candidate is
+// validated by ToNr, including the checksum.
+//
+// The confidence starts at the chance that a random candidate fails the checksum (e.g. 0.999 for two checksum runes
+// in base 31), or at UncheckedConfidence without a checksum. It is scaled by CaseFactor when the match only differs
+// from ToString in casing, and by SpacingFactor when it differs otherwise.
+func (id *ID) FindAll(text string) []Match {
+	checked := 1 - id.passRate()
+	if checked == 0 {
+		checked = UncheckedConfidence
+	}
+	matches := []Match{}
+	for _, sp := range id.find(text) {
+		m := Match{
+			Start:      sp.start,
+			End:        sp.end,
+			Text:       text[sp.start:sp.end],
+			Nr:         sp.nr,
+			Confidence: checked,
+		}
+		canonical := id.ToString(sp.nr)
+		if strings.EqualFold(m.Text, canonical) && m.Text != canonical {
+			m.Confidence *= CaseFactor
+		}
+		if !strings.EqualFold(m.Text, canonical) {
+			m.Confidence *= SpacingFactor
+		}
+		matches = append(matches, m)
+	}
+	return matches
+}
+
+// passRate is a helper that returns the chance that a random ID passes the checksum.
+func (id *ID) passRate() float64 {
+	if id.template == nil {
+		return math.Pow(float64(id.converter.Base()), -float64(id.opts.ChecksumLen))
+	}
+	rate := 1.0
+	for i := 0; i < id.template.Len(); i++ {
+		if id.template.Checksum(i) {
+			rate /= float64(utf8.RuneCountInString(id.template.Alphabet(i)))
+		}
+	}
+	return rate
+}
+
+// span is the location of an ID in a text, in byte offsets.
+type span struct {
+	start, end int
+	nr         uint64
+}
+
+// find is a helper that locates all IDs in a text. Candidates are runs of groups of ID runes, where the groups are
+// separated by spaces or tabs. Within a run, the longest range of consecutive groups that has the length of an ID and
+// that ToNr accepts is taken, so that e.g. a preceding word doesn't spoil the match.
+func (id *ID) find(text string) []span {
+	minLen, maxLen := id.lenBounds()
+	spans := []span{}
+
+	// Collect the groups of the current run; flush the run when something other than a group or a separator follows.
+	type group struct{ start, end, runes int }
+	run := []group{}
+	flush := func() {
+		for i := 0; i < len(run); {
+			// Extend the range only while it fits in maxLen runes, so that long runs are scanned in linear time.
+			last, n := i-1, 0
+			for last+1 < len(run) && n+run[last+1].runes <= maxLen {
+				last++
+				n += run[last].runes
+			}
+			found := false
+			for j := last; j >= i && !found; j-- {
+				if j < last {
+					n -= run[j+1].runes
+				}
+				if n < minLen {
+					break
+				}
+				if nr, err := id.ToNr(text[run[i].start:run[j].end]); err == nil {
+					spans = append(spans, span{start: run[i].start, end: run[j].end, nr: nr})
+					i = j + 1
+					found = true
+				}
+			}
+			if !found {
+				i++
+			}
+		}
+		run = run[:0]
+	}
+
+	inGroup := false
+	for pos, r := range text {
+		switch {
+		case id.isIDRune(r):
+			if !inGroup {
+				run = append(run, group{start: pos})
+				inGroup = true
+			}
+			run[len(run)-1].end = pos + utf8.RuneLen(r)
+			run[len(run)-1].runes++
+		case r == ' ' || r == '\t':
+			inGroup = false
+		default:
+			inGroup = false
+			flush()
+		}
+	}
+	flush()
+	return spans
+}
+
+// lenBounds is a helper that returns the minimum and maximum number of runes of an ID, excluding whitespace.
+func (id *ID) lenBounds() (int, int) {
+	version := 0
+	if id.opts.Version != 0 {
+		version = 1
+	}
+	if id.template != nil {
+		return id.template.Tokens() + version, id.template.Len() + version
+	}
+	minLen := id.expectedLen()
+	maxLen := len(id.converter.ToRunes(math.MaxUint64)) + version
+	if maxLen < minLen {
+		maxLen = minLen
+	}
+	return minLen, maxLen
+}
@@ -0,0 +1,76 @@
+package id
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFindAll(t *testing.T) {
+	n := uint64(9999999999999999999) // CNH M74 XCQ Y4Q H24
+	checked := 1 - 1.0/31/31
+
+	for _, test := range []struct {
+		text string
+		want []Match
+	}{
+		{
+			text: "Your ID is CNH M74 XCQ Y4Q H24.",
+			want: []Match{{Start: 11, End: 30, Text: "CNH M74 XCQ Y4Q H24", Nr: n, Confidence: checked}},
+		},
+		{
+			text: "Re: cnh m74 xcq y4q h24",
+			want: []Match{{Start: 4, End: 23, Text: "cnh m74 xcq y4q h24", Nr: n, Confidence: checked * CaseFactor}},
+		},
+		{
+			text: "ids:\tCNHM74  XCQY4QH24\nand 000 000 000 000 CCR",
+			want: []Match{
+				{Start: 5, End: 22, Text: "CNHM74  XCQY4QH24", Nr: n, Confidence: checked * SpacingFactor},
+				{Start: 27, End: 46, Text: "000 000 000 000 CCR", Nr: 12, Confidence: checked},
+			},
+		},
+		{
+			text: "cnhm74 xcqy4qh24",
+			want: []Match{{Start: 0, End: 16, Text: "cnhm74 xcqy4qh24", Nr: n, Confidence: checked * SpacingFactor}},
+		},
+		{
+			text: "Not an ID: CNH M74 XCQ Y4Q H25",
+			want: []Match{},
+		},
+	} {
+		got := converter.FindAll(test.text)
+		for i := range got {
+			got[i].Confidence = math.Round(got[i].Confidence*1e6) / 1e6
+		}
+		for i := range test.want {
+			test.want[i].Confidence = math.Round(test.want[i].Confidence*1e6) / 1e6
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("FindAll(%q) = %+v, want %+v", test.text, got, test.want)
+		}
+	}
+
+	unchecked, err := New(&Opts{Alphabet: "0123456789", StringLen: 4})
+	if err != nil {
+		t.Fatalf("New(0-9) returned unexpected error %v", err)
+	}
+	got := unchecked.FindAll("order 1234 shipped")
+	if len(got) != 1 || got[0].Nr != 1234 || got[0].Confidence != UncheckedConfidence {
+		t.Errorf("FindAll() without checksum = %+v, want 1234 with confidence %v", got, UncheckedConfidence)
+	}
+}
+
+func TestFindAllLongRun(t *testing.T) {
+	// A long run of groups used to take cubic time.
+	text := strings.Repeat("1 ", 3000) + "000 000 000 000 CCR"
+	start := time.Now()
+	got := converter.FindAll(text)
+	if d := time.Since(start); d > 2*time.Second {
+		t.Errorf("FindAll() of %v bytes took %v", len(text), d)
+	}
+	if len(got) != 1 || got[0].Nr != 12 || got[0].Start != 6000 {
+		t.Errorf("FindAll() of a long run = %+v, want 12 at 6000", got)
+	}
+}
@@ -4,9 +4,13 @@ package id
 
 import (
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
+	"github.com/KarelKubat/hrid/block"
 	"github.com/KarelKubat/hrid/conv"
 	"github.com/KarelKubat/hrid/er"
+	"github.com/KarelKubat/hrid/tmpl"
 )
 
 const (
@@ -21,6 +25,8 @@ const (
 	GroupSize = 3
 	// Default number of checksum runes to add to a generated ID.
 	ChecksumLen = 2
+	// MaxDerivations is the number of times that ToDerivedString tries to find a clean ID.
+	MaxDerivations = 1000
 )
 
 // Opts defines the options when constructing an ID converter.
@@ -30,16 +36,45 @@ type Opts struct {
 	IgnoreCase  bool   // When true, casing will be ignored during conversions.
 	GroupSize   int    // When non-zero, an ID will be split into space-delimited groups for readability (e.g. "0123 4567").
 	ChecksumLen int    // Number of checksum runes to add to an ID, 0 for no checksumming.
+
+	// Template, when not empty, is a pattern such as "AA-9999-A" (see package tmpl) that defines the alphabet of each
+	// position. Alphabet, StringLen, GroupSize and ChecksumLen are then ignored.
+	Template string
+
+	// Version, when not zero, is emitted as the first rune of each ID, to identify the configuration that generated it.
+	// ToNr rejects IDs that don't start with it. The version rune is not covered by the checksum. See also Versions.
+	Version rune
+
+	// Blocklist, when not nil, defines what IDs may not contain. It is consulted by ToCheckedString and
+	// ToDerivedString; ToString doesn't care.
+	Blocklist *block.List
 }
 
 // ID is the receiver that implements conversions.
 type ID struct {
 	opts      *Opts
 	converter *conv.Conv
+	template  *tmpl.Template
 }
 
 // New instantiates a converter.
 func New(o *Opts) (*ID, *er.Err) {
+	if o.IgnoreCase {
+		o.Version = unicode.ToUpper(o.Version)
+	}
+	if unicode.IsSpace(o.Version) {
+		return nil, er.Newf(er.VersionError, "version %q may not be whitespace", o.Version)
+	}
+	if o.Template != "" {
+		t, err := tmpl.New(o.Template, o.IgnoreCase)
+		if err != nil {
+			return nil, err
+		}
+		return &ID{
+			opts:     o,
+			template: t,
+		}, nil
+	}
 	if o.IgnoreCase {
 		o.Alphabet = strings.ToUpper(o.Alphabet)
 	}
@@ -53,8 +88,31 @@ func New(o *Opts) (*ID, *er.Err) {
 	}, nil
 }
 
-// ToRunes converts a uint64 to a slice of runes.
+// ToRunes converts a uint64 to a slice of runes. When the ID is template-driven and the number exceeds the capacity of
+// the template, then nil is returned; use ToCheckedString to see the error.
 func (id *ID) ToRunes(n uint64) []rune {
+	out, _ := id.toRunes(n)
+	return out
+}
+
+// toRunes is a helper that converts a uint64 to a slice of runes, or returns an error when this isn't possible.
+func (id *ID) toRunes(n uint64) ([]rune, *er.Err) {
+	out, err := id.body(n)
+	if err != nil || id.opts.Version == 0 {
+		return out, err
+	}
+	version := []rune{id.opts.Version}
+	if id.template == nil && id.opts.GroupSize > 0 {
+		version = append(version, ' ')
+	}
+	return append(version, out...), nil
+}
+
+// body is a helper that converts a uint64 to a slice of runes, without the version.
+func (id *ID) body(n uint64) ([]rune, *er.Err) {
+	if id.template != nil {
+		return id.template.ToRunes(n)
+	}
 	out := id.converter.ToRunes(n)
 
 	// Prepend the first alphabet rune until the desired length is reached.
@@ -77,7 +135,7 @@ func (id *ID) ToRunes(n uint64) []rune {
 		}
 		out = formatted
 	}
-	return out
+	return out, nil
 }
 
 // ToString converts a uint64 to a string.
@@ -85,8 +143,103 @@ func (id *ID) ToString(n uint64) string {
 	return string(id.ToRunes(n))
 }
 
+// Check returns an error when the ID s hits the blocklist. When there's no blocklist, any ID is fine. The version rune
+// of a versioned ID isn't checked, so that the padding after it is allowed to repeat.
+func (id *ID) Check(s string) *er.Err {
+	if id.opts.Blocklist == nil {
+		return nil
+	}
+	if id.opts.Version != 0 {
+		s = strings.TrimLeftFunc(s, unicode.IsSpace)
+		_, size := utf8.DecodeRuneInString(s)
+		s = s[size:]
+	}
+	pad := rune(0)
+	if id.converter != nil {
+		pad = id.converter.FirstRune()
+	}
+	return id.opts.Blocklist.Check([]rune(strings.Join(strings.Fields(s), "")), pad)
+}
+
+// ToCheckedString is like ToString, but returns an error when the ID hits the blocklist, or when a template-driven ID
+// can't represent the number. For sequential IDs, the caller should skip a number that hits the blocklist and try the
+// next one.
+func (id *ID) ToCheckedString(n uint64) (string, *er.Err) {
+	runes, err := id.toRunes(n)
+	if err != nil {
+		return "", err
+	}
+	s := string(runes)
+	if err := id.Check(s); err != nil {
+		return "", err
+	}
+	return s, nil
+}
+
+// ToDerivedString is meant for random or obfuscated IDs. When the ID for n hits the blocklist, derive is called to
+// compute a next value, until a clean ID is found. The clean ID is returned, with the value that it represents. An
+// error occurs when no clean ID is found within MaxDerivations attempts, or when an ID can't be generated at all,
+// e.g. because a template can't represent the number; the latter error is returned as is.
+func (id *ID) ToDerivedString(n uint64, derive func(uint64) uint64) (string, uint64, *er.Err) {
+	for i := 0; i < MaxDerivations; i++ {
+		s, err := id.ToCheckedString(n)
+		switch {
+		case err == nil:
+			return s, n, nil
+		case err.Code != er.BlockedWordError && err.Code != er.RunTooLongError:
+			return "", 0, err
+		}
+		n = derive(n)
+	}
+	return "", 0, er.Newf(er.TooManyDerivationsError, "no clean ID found after %v derivations", MaxDerivations)
+}
+
+// ChecksumRunes returns the checksum runes of the ID of n, in the order in which they occur. The string is empty when
+// the converter doesn't checksum. An error occurs when a template-driven ID can't represent the number.
+func (id *ID) ChecksumRunes(n uint64) (string, *er.Err) {
+	if id.template != nil {
+		runes, err := id.template.ToRunes(n)
+		if err != nil {
+			return "", err
+		}
+		out := []rune{}
+		for i, r := range runes {
+			if id.template.Checksum(i) {
+				out = append(out, r)
+			}
+		}
+		return string(out), nil
+	}
+	runes := id.converter.ToRunes(n)
+	return string(runes[len(runes)-id.opts.ChecksumLen:]), nil
+}
+
+// Version returns the version rune that starts each ID, or zero.
+func (id *ID) Version() rune {
+	return id.opts.Version
+}
+
+// Template returns the template of a template-driven ID, or nil.
+func (id *ID) Template() *tmpl.Template {
+	return id.template
+}
+
 // ToNr converts a string to a uint64.
 func (id *ID) ToNr(s string) (uint64, *er.Err) {
+	if id.opts.Version != 0 {
+		s = strings.TrimLeftFunc(s, unicode.IsSpace)
+		v, size := utf8.DecodeRuneInString(s)
+		if id.opts.IgnoreCase {
+			v = unicode.ToUpper(v)
+		}
+		if v != id.opts.Version {
+			return 0, er.Newf(er.UnknownVersionError, "ID %q doesn't start with version %v", s, string(id.opts.Version))
+		}
+		s = s[size:]
+	}
+	if id.template != nil {
+		return id.template.ToNr(strings.TrimSpace(s))
+	}
 	if id.opts.IgnoreCase {
 		s = strings.ToUpper(s)
 	}
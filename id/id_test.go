@@ -1,6 +1,11 @@
 package id
 
-import "testing"
+import (
+	"testing"
+
+	"github.com/KarelKubat/hrid/block"
+	"github.com/KarelKubat/hrid/er"
+)
 
 func TestConversions(t *testing.T) {
 	for _, n := range []uint64{
@@ -92,3 +97,137 @@ func TestFormattingWithoutChecksum(t *testing.T) {
 		}
 	}
 }
+
+func TestBlocklist(t *testing.T) {
+	id, err := New(&Opts{
+		Alphabet:  "0123456789ABCDEF",
+		StringLen: 8,
+		Blocklist: &block.List{Words: []string{"DEAD"}, MaxRun: 3},
+	})
+	if err != nil {
+		t.Fatalf("New() = _,%v, need nil error", err)
+	}
+
+	// Sequential use: the hit is reported.
+	if _, err := id.ToCheckedString(0xDEAD); err == nil || err.Code != er.BlockedWordError {
+		t.Errorf("id.ToCheckedString(0xDEAD) = _,%v, want BlockedWordError", err)
+	}
+	if _, err := id.ToCheckedString(0xFFFF1); err == nil || err.Code != er.RunTooLongError {
+		t.Errorf("id.ToCheckedString(0xFFFF1) = _,%v, want RunTooLongError", err)
+	}
+	if s, err := id.ToCheckedString(0xDEAE); err != nil || s != "000DEAE" {
+		t.Errorf("id.ToCheckedString(0xDEAE) = %q,%v, want 000DEAE,nil", s, err)
+	}
+
+	// Random use: the value is re-derived.
+	s, n, err := id.ToDerivedString(0xDEAD, func(n uint64) uint64 { return n * 2 })
+	if err != nil {
+		t.Fatalf("id.ToDerivedString(0xDEAD) = _,_,%v, need nil error", err)
+	}
+	if s != "001BD5A" || n != 0x1BD5A {
+		t.Errorf("id.ToDerivedString(0xDEAD) = %q,%x,_, want 001BD5A,1bd5a", s, n)
+	}
+	if _, _, err := id.ToDerivedString(0xDEAD, func(n uint64) uint64 { return n }); err == nil || err.Code != er.TooManyDerivationsError {
+		t.Errorf("id.ToDerivedString() with a stuck derivation = _,_,%v, want TooManyDerivationsError", err)
+	}
+
+	// Errors other than blocklist hits are not re-derived.
+	tid, err := New(&Opts{
+		Template:  "99",
+		Blocklist: &block.List{MaxRun: 3},
+	})
+	if err != nil {
+		t.Fatalf("New(99) = _,%v, need nil error", err)
+	}
+	if _, _, err := tid.ToDerivedString(100, func(n uint64) uint64 { return n / 10 }); err == nil || err.Code != er.OutOfRangeError {
+		t.Errorf("tid.ToDerivedString(100) = _,_,%v, want OutOfRangeError", err)
+	}
+}
+
+func TestVersionedBlocklist(t *testing.T) {
+	for _, test := range []struct {
+		version    rune
+		groupSize  int
+		n          uint64
+		wantString string
+		wantCode   er.Code
+	}{
+		// The padding after the version rune is exempt, as it is without a version.
+		{version: 0, groupSize: 3, n: 12, wantString: "000 000 000 000 CCR"},
+		{version: 'V', groupSize: 3, n: 12, wantString: "V 000 000 000 000 CCR"},
+		{version: 'V', groupSize: 0, n: 12, wantString: "V000000000000CCR"},
+		// Runs elsewhere are still caught: 31^4-1 is YYYY.
+		{version: 'V', groupSize: 0, n: 923520, wantCode: er.RunTooLongError},
+	} {
+		id, err := New(&Opts{
+			Alphabet:    Alphabet,
+			StringLen:   StringLen,
+			IgnoreCase:  IgnoreCase,
+			GroupSize:   test.groupSize,
+			ChecksumLen: ChecksumLen,
+			Version:     test.version,
+			Blocklist:   &block.List{MaxRun: 3},
+		})
+		if err != nil {
+			t.Fatalf("New() = _,%v, need nil error", err)
+		}
+		s, err := id.ToCheckedString(test.n)
+		switch {
+		case test.wantCode != 0 && (err == nil || err.Code != test.wantCode):
+			t.Errorf("id.ToCheckedString(%v) with version %q = _,%v, want %v", test.n, test.version, err, test.wantCode)
+		case test.wantCode == 0 && (err != nil || s != test.wantString):
+			t.Errorf("id.ToCheckedString(%v) with version %q = %q,%v, want %q,nil", test.n, test.version, s, err, test.wantString)
+		}
+	}
+}
+
+func TestTemplate(t *testing.T) {
+	id, err := New(&Opts{
+		Template:   "AA-9999-A",
+		IgnoreCase: true,
+		GroupSize:  2, // ignored
+	})
+	if err != nil {
+		t.Fatalf("New() = _,%v, need nil error", err)
+	}
+	if s := id.ToString(21); s != "AA-0001-A" {
+		t.Errorf("id.ToString(21) = %q, want AA-0001-A", s)
+	}
+	if n, err := id.ToNr(" aa-0001-a "); err != nil || n != 21 {
+		t.Errorf("id.ToNr(aa-0001-a) = %v,%v, want 21,nil", n, err)
+	}
+	if s := id.ToString(1 << 40); s != "" {
+		t.Errorf("id.ToString(1<<40) = %q, want empty string", s)
+	}
+	if _, err := id.ToCheckedString(1 << 40); err == nil || err.Code != er.OutOfRangeError {
+		t.Errorf("id.ToCheckedString(1<<40) = _,%v, want OutOfRangeError", err)
+	}
+}
+
+func TestChecksumRunes(t *testing.T) {
+	for _, test := range []struct {
+		opts     *Opts
+		n        uint64
+		want     string
+		wantCode er.Code
+	}{
+		{opts: &Opts{Alphabet: Alphabet, StringLen: StringLen, GroupSize: GroupSize, ChecksumLen: ChecksumLen}, n: 12,
+			want: "CR"},
+		{opts: &Opts{Alphabet: "0123456789", StringLen: 4, Version: 'v'}, n: 12, want: ""},
+		{opts: &Opts{Template: "AA-9999-#"}, n: 12345, want: "F"},
+		{opts: &Opts{Template: "9-#"}, n: 10, wantCode: er.OutOfRangeError},
+	} {
+		c, err := New(test.opts)
+		if err != nil {
+			t.Fatalf("New(%+v) = _,%v, need nil error", test.opts, err)
+		}
+		got, err := c.ChecksumRunes(test.n)
+		gotCode := er.None
+		if err != nil {
+			gotCode = err.Code
+		}
+		if got != test.want || gotCode != test.wantCode {
+			t.Errorf("ChecksumRunes(%v) = %q,%v, want %q,%v", test.n, got, gotCode, test.want, test.wantCode)
+		}
+	}
+}
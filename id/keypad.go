@@ -0,0 +1,245 @@
+package id
+
+import (
+	"math/rand"
+	"strings"
+	"unicode"
+
+	"github.com/KarelKubat/hrid/er"
+)
+
+const (
+	// KeypadEscape on a phone keypad means that the next key is a literal digit, not one of its letters. The keys 0
+	// and 1 carry no letters, so they are always literal.
+	KeypadEscape = '*'
+	// MaxKeypadMatches is the maximum number of matches that FromKeypad returns.
+	MaxKeypadMatches = 100
+)
+
+// Keypad maps the keys of a phone keypad to the letters that they cover.
+var Keypad = map[rune]string{
+	'2': "ABC", '3': "DEF", '4': "GHI", '5': "JKL", '6': "MNO", '7': "PQRS", '8': "TUV", '9': "WXYZ",
+}
+
+// KeypadMatch is an ID that's compatible with a sequence of keys.
+type KeypadMatch struct {
+	ID string // The ID, as ToString would generate it.
+	Nr uint64 // The number that the ID represents.
+}
+
+// KeypadStats describes how often keypad entry is ambiguous for a configuration, see KeypadAmbiguity.
+type KeypadStats struct {
+	Samples   int     // The number of IDs that were tried.
+	Ambiguous int     // The number of IDs for which the keys matched more than one valid ID.
+	Mean      float64 // The mean number of valid IDs that match the keys of an ID.
+	Worst     uint64  // The highest number of valid IDs that match the keys of an ID.
+}
+
+// ToKeypad returns the keys to press to enter an ID on a phone keypad. Digits other than 0 and 1 are preceded by
+// KeypadEscape. Runes that aren't on the keypad are dropped.
+func ToKeypad(s string) string {
+	var out strings.Builder
+	for _, r := range strings.ToUpper(s) {
+		switch {
+		case r == '0' || r == '1':
+			out.WriteRune(r)
+		case r >= '2' && r <= '9':
+			out.WriteRune(KeypadEscape)
+			out.WriteRune(r)
+		default:
+			for key, letters := range Keypad {
+				if strings.ContainsRune(letters, r) {
+					out.WriteRune(key)
+				}
+			}
+		}
+	}
+	return out.String()
+}
+
+// FromKeypad decodes a sequence of keys that was entered on a phone keypad, such as an IVR line. Each key stands for
+// one of its letters that's in the alphabet; see KeypadEscape for entering digits. All IDs that are compatible with
+// the keys and that pass the checksum are collected (up to MaxKeypadMatches). When known is not nil, only IDs for which
+// it returns true are kept. When exactly one ID remains, it is returned. When more remain, they are returned with an
+// AmbiguousError; when none remain, a NoMatchError occurs. Template-driven IDs aren't supported.
+func (id *ID) FromKeypad(keys string, known func(uint64) bool) ([]KeypadMatch, *er.Err) {
+	options, err := id.keypadOptions(keys)
+	if err != nil {
+		return nil, err
+	}
+
+	matches := []KeypadMatch{}
+	feasible := id.keypadFeasible(options)
+	runes := make([]rune, len(options))
+	var walk func(i, state int)
+	walk = func(i, state int) {
+		if len(matches) >= MaxKeypadMatches {
+			return
+		}
+		if i == len(options) {
+			n, err := id.ToNr(string(runes))
+			if err != nil || known != nil && !known(n) {
+				return
+			}
+			matches = append(matches, KeypadMatch{ID: id.ToString(n), Nr: n})
+			return
+		}
+		for _, o := range options[i] {
+			next, ok := id.keypadStep(i, len(options), state, o.value)
+			if ok && feasible[i+1][next] {
+				runes[i] = o.r
+				walk(i+1, next)
+			}
+		}
+	}
+	walk(0, 0)
+
+	switch len(matches) {
+	case 0:
+		return nil, er.Newf(er.NoMatchError, "no valid ID matches keys %q", keys)
+	case 1:
+		return matches, nil
+	case MaxKeypadMatches:
+		return matches, er.Newf(er.AmbiguousError, "keys %q match %v or more valid IDs", keys, len(matches))
+	default:
+		return matches, er.Newf(er.AmbiguousError, "keys %q match %v valid IDs", keys, len(matches))
+	}
+}
+
+// KeypadAmbiguity reports how often keypad entry is ambiguous for a configuration, by trying a number of random IDs.
+func KeypadAmbiguity(o *Opts, samples int) (*KeypadStats, *er.Err) {
+	c, err := New(o)
+	if err != nil {
+		return nil, err
+	}
+	stats := &KeypadStats{
+		Samples: samples,
+	}
+	rnd := rand.New(rand.NewSource(1))
+	total := uint64(0)
+	for i := 0; i < samples; i++ {
+		options, err := c.keypadOptions(ToKeypad(c.ToString(rnd.Uint64())))
+		if err != nil {
+			return nil, err
+		}
+		n := c.keypadCount(options)
+		total += n
+		if n > 1 {
+			stats.Ambiguous++
+		}
+		if n > stats.Worst {
+			stats.Worst = n
+		}
+	}
+	if samples > 0 {
+		stats.Mean = float64(total) / float64(samples)
+	}
+	return stats, nil
+}
+
+// keypadOption is a rune that a key can stand for at some position, with its value in the alphabet.
+type keypadOption struct {
+	r     rune
+	value int
+}
+
+// keypadOptions is a helper that lists for each position of the keys the runes that it can stand for.
+func (id *ID) keypadOptions(keys string) ([][]keypadOption, *er.Err) {
+	if id.template != nil {
+		return nil, er.New(er.PatternError, "keypad entry isn't supported for template-driven IDs")
+	}
+	options := [][]keypadOption{}
+	runes := []rune(keys)
+	for i := 0; i < len(runes); i++ {
+		candidates := ""
+		switch k := runes[i]; {
+		case k == KeypadEscape:
+			if i+1 == len(runes) {
+				return nil, er.Newf(er.NoSuchTokenError, "keys %q end in %v", keys, string(KeypadEscape))
+			}
+			i++
+			candidates = string(runes[i])
+		case k == '0' || k == '1':
+			candidates = string(k)
+		default:
+			candidates = Keypad[k]
+			if !id.opts.IgnoreCase {
+				candidates += strings.ToLower(candidates)
+			}
+		}
+
+		opts := []keypadOption{}
+		for _, r := range candidates {
+			if len(options) == 0 && id.opts.Version != 0 {
+				if r == id.opts.Version || id.opts.IgnoreCase && unicode.ToUpper(r) == id.opts.Version {
+					opts = append(opts, keypadOption{r: id.opts.Version})
+				}
+				continue
+			}
+			if value, err := id.converter.Index(r); err == nil {
+				opts = append(opts, keypadOption{r: r, value: value})
+			}
+		}
+		if len(opts) == 0 {
+			return nil, er.Newf(er.NoSuchTokenError, "key %v at position %v matches nothing in the alphabet",
+				string(runes[i]), len(options)+1)
+		}
+		options = append(options, opts)
+	}
+	return options, nil
+}
+
+// keypadStep is a helper that returns the checksum state after position i holds a value, and whether that's possible.
+// The state is the sum of all values so far, modulo the base. A checksum position can only hold the current state.
+func (id *ID) keypadStep(i, n, state, value int) (int, bool) {
+	if i == 0 && id.opts.Version != 0 {
+		return state, true
+	}
+	base := id.converter.Base()
+	if i >= n-id.opts.ChecksumLen && value != state {
+		return 0, false
+	}
+	return (state + value) % base, true
+}
+
+// keypadFeasible is a helper that computes, per position and checksum state, whether the remaining positions can be
+// completed so that all checksums match.
+func (id *ID) keypadFeasible(options [][]keypadOption) [][]bool {
+	counts := id.keypadCounts(options)
+	feasible := make([][]bool, len(counts))
+	for i := range counts {
+		feasible[i] = make([]bool, len(counts[i]))
+		for s, c := range counts[i] {
+			feasible[i][s] = c > 0
+		}
+	}
+	return feasible
+}
+
+// keypadCount is a helper that returns the number of runes sequences that match the options and pass the checksum.
+func (id *ID) keypadCount(options [][]keypadOption) uint64 {
+	return id.keypadCounts(options)[0][0]
+}
+
+// keypadCounts is a helper that computes, per position and checksum state, the number of ways in which the remaining
+// positions can be completed so that all checksums match.
+func (id *ID) keypadCounts(options [][]keypadOption) [][]uint64 {
+	base := id.converter.Base()
+	counts := make([][]uint64, len(options)+1)
+	for i := range counts {
+		counts[i] = make([]uint64, base)
+	}
+	for s := range counts[len(options)] {
+		counts[len(options)][s] = 1
+	}
+	for i := len(options) - 1; i >= 0; i-- {
+		for s := 0; s < base; s++ {
+			for _, o := range options[i] {
+				if next, ok := id.keypadStep(i, len(options), s, o.value); ok {
+					counts[i][s] += counts[i+1][next]
+				}
+			}
+		}
+	}
+	return counts
+}
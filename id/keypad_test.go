@@ -0,0 +1,92 @@
+package id
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/KarelKubat/hrid/er"
+)
+
+func TestToKeypad(t *testing.T) {
+	for _, test := range []struct {
+		s    string
+		want string
+	}{
+		{s: "A0A4", want: "202*4"},
+		{s: "cnh m74", want: "2646*7*4"},
+		{s: "01 23", want: "01*2*3"},
+	} {
+		if got := ToKeypad(test.s); got != test.want {
+			t.Errorf("ToKeypad(%q) = %q, want %q", test.s, got, test.want)
+		}
+	}
+}
+
+func TestFromKeypad(t *testing.T) {
+	hex, err := New(&Opts{Alphabet: "0123456789ABCDEF", IgnoreCase: true, ChecksumLen: 2})
+	if err != nil {
+		t.Fatalf("New() = _,%v, need nil error", err)
+	}
+
+	// Only A0A4 passes the checksum: B0B6 and C0C8 would need other keys at the end.
+	matches, err := hex.FromKeypad("202*4", nil)
+	if err != nil || len(matches) != 1 || matches[0].ID != "A0A4" || matches[0].Nr != 0xA0 {
+		t.Errorf("FromKeypad(202*4) = %+v,%v, want A0A4,nil", matches, err)
+	}
+
+	// 2 2 as AB or BA (both sum to 21, so both have checksums 5 and A): ambiguous, unless we know which exist.
+	matches, err = hex.FromKeypad("22*52", nil)
+	if err == nil || err.Code != er.AmbiguousError || len(matches) != 2 {
+		t.Errorf("FromKeypad(22*52) = %+v,%v, want 2 matches and AmbiguousError", matches, err)
+	}
+	if err != nil && strings.Contains(err.Msg, "or more") {
+		t.Errorf("FromKeypad(22*52) = _,%v, want an exact count", err)
+	}
+	matches, err = hex.FromKeypad("22*52", func(n uint64) bool { return n == 0xBA })
+	if err != nil || len(matches) != 1 || matches[0].Nr != 0xBA {
+		t.Errorf("FromKeypad(22*52) with a lookup = %+v,%v, want BA5A,nil", matches, err)
+	}
+
+	for _, keys := range []string{"202*5", "2*"} {
+		if _, err := hex.FromKeypad(keys, nil); err == nil {
+			t.Errorf("FromKeypad(%q) = _,nil, want error", keys)
+		}
+	}
+
+	// Without a checksum, 2222222 matches 3^7 IDs: the count stops at MaxKeypadMatches.
+	unchecked, err := New(&Opts{Alphabet: "0123456789ABCDEF", IgnoreCase: true})
+	if err != nil {
+		t.Fatalf("New() = _,%v, need nil error", err)
+	}
+	matches, err = unchecked.FromKeypad("2222222", nil)
+	if err == nil || len(matches) != MaxKeypadMatches || !strings.Contains(err.Msg, "or more") {
+		t.Errorf("FromKeypad(2222222) = %v matches,%v, want %v or more", len(matches), err, MaxKeypadMatches)
+	}
+
+	// Round trip for the default converter, using a lookup.
+	n := uint64(9999999999999999999)
+	matches, err = converter.FromKeypad(ToKeypad(ToString(n)), func(k uint64) bool { return k == n })
+	if err != nil || len(matches) != 1 || matches[0].ID != ToString(n) {
+		t.Errorf("FromKeypad(ToKeypad(%v)) with a lookup = %+v,%v, want %q", n, matches, err, ToString(n))
+	}
+}
+
+func TestKeypadAmbiguity(t *testing.T) {
+	// Digits only: all keys are literal, nothing is ambiguous.
+	stats, err := KeypadAmbiguity(&Opts{Alphabet: "0123456789", ChecksumLen: 1}, 50)
+	if err != nil {
+		t.Fatalf("KeypadAmbiguity() = _,%v, need nil error", err)
+	}
+	if stats.Samples != 50 || stats.Ambiguous != 0 || stats.Mean != 1 || stats.Worst != 1 {
+		t.Errorf("KeypadAmbiguity(digits) = %+v, want no ambiguity", stats)
+	}
+
+	// The default alphabet has up to 4 letters per key.
+	stats, err = KeypadAmbiguity(&Opts{Alphabet: Alphabet, StringLen: StringLen, IgnoreCase: true, ChecksumLen: 2}, 50)
+	if err != nil {
+		t.Fatalf("KeypadAmbiguity() = _,%v, need nil error", err)
+	}
+	if stats.Ambiguous == 0 || stats.Mean <= 1 {
+		t.Errorf("KeypadAmbiguity(default) = %+v, want ambiguity", stats)
+	}
+}
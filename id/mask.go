@@ -0,0 +1,143 @@
+package id
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/KarelKubat/hrid/er"
+)
+
+const (
+	// MaskRune is the default rune that replaces hidden tokens of a masked ID.
+	MaskRune = '*'
+)
+
+// Mask defines how IDs are masked, e.g. for logs and receipts. At least one token is always hidden, even when Leading
+// and Trailing would reveal the whole ID.
+type Mask struct {
+	Leading  int  // Number of tokens to reveal at the start of an ID.
+	Trailing int  // Number of tokens to reveal at the end of an ID.
+	Rune     rune // Rune that replaces hidden tokens, zero means MaskRune.
+}
+
+// maskRune is a helper that returns the mask rune for this converter, or an error when ToNr would accept it.
+func (id *ID) maskRune(m *Mask) (rune, *er.Err) {
+	r := m.Rune
+	if r == 0 {
+		r = MaskRune
+	}
+	if unicode.IsSpace(r) || id.isToken(r) {
+		return 0, er.Newf(er.MaskRuneError, "mask rune %q could be taken as part of an ID", r)
+	}
+	return r, nil
+}
+
+// ToMaskedString converts a uint64 to a string as ToString does, but with the tokens hidden that m doesn't reveal.
+// An error occurs when the mask rune could be taken as a token of an ID.
+func (id *ID) ToMaskedString(n uint64, m *Mask) (string, *er.Err) {
+	mr, err := id.maskRune(m)
+	if err != nil {
+		return "", err
+	}
+	return id.mask([]rune(id.ToString(n)), m, mr), nil
+}
+
+// MaskString masks an ID. The ID is first decoded, so that the result has the canonical form of ToString. An error
+// occurs when s isn't a valid ID, or when the mask rune could be taken as a token.
+func (id *ID) MaskString(s string, m *Mask) (string, *er.Err) {
+	n, err := id.ToNr(s)
+	if err != nil {
+		return "", err
+	}
+	return id.ToMaskedString(n, m)
+}
+
+// MaskText finds all IDs in a free text and masks them in place, leaving the spacing as it was. An error occurs when
+// the mask rune could be taken as a token.
+func (id *ID) MaskText(text string, m *Mask) (string, *er.Err) {
+	mr, err := id.maskRune(m)
+	if err != nil {
+		return "", err
+	}
+	var out strings.Builder
+	prev := 0
+	for _, sp := range id.find(text) {
+		out.WriteString(text[prev:sp.start])
+		out.WriteString(id.mask([]rune(text[sp.start:sp.end]), m, mr))
+		prev = sp.end
+	}
+	out.WriteString(text[prev:])
+	return out.String(), nil
+}
+
+// mask is a helper that replaces the tokens of an ID with the mask rune, except for the ones that m reveals.
+func (id *ID) mask(runes []rune, m *Mask, mr rune) string {
+	tokens := 0
+	for _, r := range runes {
+		if id.isToken(r) {
+			tokens++
+		}
+	}
+	leading, trailing := m.Leading, m.Trailing
+	for leading+trailing >= tokens && leading+trailing > 0 {
+		if trailing > 0 {
+			trailing--
+		} else {
+			leading--
+		}
+	}
+
+	out := make([]rune, len(runes))
+	t := 0
+	for i, r := range runes {
+		out[i] = r
+		if !id.isToken(r) {
+			continue
+		}
+		if t >= leading && t < tokens-trailing {
+			out[i] = mr
+		}
+		t++
+	}
+	return string(out)
+}
+
+// isToken is a helper that returns true when the rune can be a token of an ID: a rune of the alphabet (or of any
+// position of a template), or the version rune.
+func (id *ID) isToken(r rune) bool {
+	if id.opts.IgnoreCase {
+		r = unicode.ToUpper(r)
+	}
+	if id.opts.Version != 0 && r == id.opts.Version {
+		return true
+	}
+	if id.template != nil {
+		for i := 0; i < id.template.Len(); i++ {
+			if _, ok := id.template.Literal(i); !ok && id.template.Accepts(i, r) {
+				return true
+			}
+		}
+		return false
+	}
+	_, err := id.converter.Index(r)
+	return err == nil
+}
+
+// isIDRune is a helper that returns true when the rune can be part of an ID: a token, or a literal of a template.
+func (id *ID) isIDRune(r rune) bool {
+	if id.isToken(r) {
+		return true
+	}
+	if id.template == nil {
+		return false
+	}
+	if id.opts.IgnoreCase {
+		r = unicode.ToUpper(r)
+	}
+	for i := 0; i < id.template.Len(); i++ {
+		if lit, ok := id.template.Literal(i); ok && lit == r {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,90 @@
+package id
+
+import (
+	"testing"
+
+	"github.com/KarelKubat/hrid/er"
+)
+
+func TestToMaskedString(t *testing.T) {
+	n := uint64(9999999999999999999) // CNH M74 XCQ Y4Q H24
+	for _, test := range []struct {
+		mask       Mask
+		wantString string
+		wantCode   er.Code
+	}{
+		{
+			mask:       Mask{Trailing: 6},
+			wantString: "*** *** *** Y4Q H24",
+		},
+		{
+			mask:       Mask{Leading: 2, Trailing: 2, Rune: '#'},
+			wantString: "CN# ### ### ### #24",
+		},
+		{
+			mask:       Mask{Leading: 10, Trailing: 10},
+			wantString: "CNH M74 XCQ Y*Q H24",
+		},
+		{
+			mask:     Mask{Rune: 'x'}, // ignorecase, so x is a token
+			wantCode: er.MaskRuneError,
+		},
+		{
+			mask:     Mask{Rune: ' '},
+			wantCode: er.MaskRuneError,
+		},
+	} {
+		gotString, err := converter.ToMaskedString(n, &test.mask)
+		gotCode := er.None
+		if err != nil {
+			gotCode = err.Code
+		}
+		if gotString != test.wantString || gotCode != test.wantCode {
+			t.Errorf("ToMaskedString(%v, %+v) = %q,%v, want %q,%v",
+				n, test.mask, gotString, err, test.wantString, test.wantCode)
+		}
+		if err == nil {
+			if _, err := converter.ToNr(gotString); err == nil {
+				t.Errorf("ToNr(%q) = _,nil, masked IDs must not be accepted", gotString)
+			}
+		}
+	}
+}
+
+func TestMaskString(t *testing.T) {
+	got, err := converter.MaskString("cnhm74xcqy4qh24", &Mask{Trailing: 3})
+	if err != nil || got != "*** *** *** *** H24" {
+		t.Errorf("MaskString() = %q,%v, want %q,nil", got, err, "*** *** *** *** H24")
+	}
+	if _, err := converter.MaskString("cnhm74xcqy4qh25", &Mask{}); err == nil || err.Code != er.ChecksumError {
+		t.Errorf("MaskString() of an invalid ID = _,%v, want ChecksumError", err)
+	}
+}
+
+func TestMaskText(t *testing.T) {
+	for _, test := range []struct {
+		text string
+		want string
+	}{
+		{
+			text: "Your ID is CNH M74 XCQ Y4Q H24.",
+			want: "Your ID is *** *** *** Y4Q H24.",
+		},
+		{
+			text: "THE cnhm74 xcqy4qh24, and 000 000 000 000 CCR",
+			want: "THE ****** ***y4qh24, and *** *** *** 000 CCR",
+		},
+		{
+			text: "Not an ID: CNH M74 XCQ Y4Q H25",
+			want: "Not an ID: CNH M74 XCQ Y4Q H25",
+		},
+	} {
+		got, err := converter.MaskText(test.text, &Mask{Trailing: 6})
+		if err != nil {
+			t.Fatalf("MaskText(%q) = _,%v, need nil error", test.text, err)
+		}
+		if got != test.want {
+			t.Errorf("MaskText(%q) = %q, want %q", test.text, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package id
+
+import (
+	"strings"
+
+	"github.com/KarelKubat/hrid/er"
+)
+
+// MultiDecoder decodes IDs using an ordered list of converters. This is meant for migrations, where users still hold
+// IDs of an older configuration.
+type MultiDecoder struct {
+	converters []*ID
+}
+
+// Decoded is the result of MultiDecoder.ToNr.
+type Decoded struct {
+	Nr        uint64   // The number, as decoded by the first converter that accepted the ID.
+	Index     int      // The index of the first converter that accepted the ID.
+	Matches   []int    // The indexes of all converters that accepted the ID.
+	Nrs       []uint64 // The numbers of all converters that accepted the ID, in the order of Matches.
+	Ambiguous bool     // True when more than one converter accepted the ID.
+}
+
+// NewMultiDecoder returns a MultiDecoder that tries the converters in the given order.
+func NewMultiDecoder(converters ...*ID) *MultiDecoder {
+	return &MultiDecoder{
+		converters: converters,
+	}
+}
+
+// ToNr converts a string to a uint64 by trying each converter. All converters are tried, so that an ID which is
+// accepted by more than one converter is flagged as ambiguous; the number of the first match is returned, and those of
+// all matches are listed. An error occurs when no converter accepts the ID. When there is one converter, or when all
+// converters fail with the same code, the error has that code; otherwise, it is a NoMatchError.
+func (m *MultiDecoder) ToNr(s string) (*Decoded, *er.Err) {
+	var d *Decoded
+	errs := []*er.Err{}
+	for i, c := range m.converters {
+		n, err := c.ToNr(s)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if d == nil {
+			d = &Decoded{
+				Nr:    n,
+				Index: i,
+			}
+		}
+		d.Matches = append(d.Matches, i)
+		d.Nrs = append(d.Nrs, n)
+	}
+	if d == nil {
+		return nil, noMatch(s, errs)
+	}
+	d.Ambiguous = len(d.Matches) > 1
+	return d, nil
+}
+
+// noMatch is a helper that returns the error of MultiDecoder.ToNr when none of the converters accepts s.
+func noMatch(s string, errs []*er.Err) *er.Err {
+	if len(errs) == 1 {
+		return errs[0]
+	}
+	code := errs[0].Code
+	reasons := []string{}
+	for _, err := range errs {
+		if err.Code != code {
+			code = er.NoMatchError
+		}
+		reasons = append(reasons, err.Error())
+	}
+	return er.Newf(code, "no converter accepts %q: %v", s, strings.Join(reasons, "; "))
+}
@@ -0,0 +1,81 @@
+package id
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/KarelKubat/hrid/er"
+)
+
+func TestMultiDecoder(t *testing.T) {
+	hex, err := New(&Opts{
+		Alphabet:   "0123456789ABCDEF",
+		StringLen:  9,
+		IgnoreCase: true,
+		GroupSize:  4,
+	})
+	if err != nil {
+		t.Fatalf("New(hex) = _,%v, need nil error", err)
+	}
+	m := NewMultiDecoder(converter, hex)
+
+	for _, test := range []struct {
+		s             string
+		wantNr        uint64
+		wantIndex     int
+		wantAmbiguous bool
+		wantNrs       []uint64
+		wantCode      er.Code
+	}{
+		{s: ToString(12345), wantNr: 12345, wantIndex: 0, wantNrs: []uint64{12345}},
+		{s: "dead beef", wantNr: 0xDEADBEEF, wantIndex: 1, wantNrs: []uint64{0xDEADBEEF}},
+		{s: "0000 0000", wantNr: 0, wantIndex: 0, wantAmbiguous: true, wantNrs: []uint64{0, 0}}, // checksum of zeroes is zero
+		{s: ToString(1), wantNr: 1, wantIndex: 0, wantAmbiguous: true, wantNrs: []uint64{1, 0x112}},
+		{s: "ZZZ", wantCode: er.NoMatchError},
+	} {
+		d, err := m.ToNr(test.s)
+		if test.wantCode != er.None {
+			if err == nil || err.Code != test.wantCode {
+				t.Errorf("m.ToNr(%q) = _,%v, want %v", test.s, err, test.wantCode)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("m.ToNr(%q) = _,%v, need nil error", test.s, err)
+		}
+		if d.Nr != test.wantNr || d.Index != test.wantIndex || d.Ambiguous != test.wantAmbiguous ||
+			!reflect.DeepEqual(d.Nrs, test.wantNrs) {
+			t.Errorf("m.ToNr(%q) = %+v, want nr %v, index %v, ambiguous %v, nrs %v",
+				test.s, d, test.wantNr, test.wantIndex, test.wantAmbiguous, test.wantNrs)
+		}
+	}
+}
+
+func TestMultiDecoderErrors(t *testing.T) {
+	hex, err := New(&Opts{Alphabet: "0123456789ABCDEF"})
+	if err != nil {
+		t.Fatalf("New(hex) = _,%v, need nil error", err)
+	}
+	short, err := New(&Opts{Alphabet: Alphabet, StringLen: 4, IgnoreCase: true, ChecksumLen: 2})
+	if err != nil {
+		t.Fatalf("New(short) = _,%v, need nil error", err)
+	}
+
+	for _, test := range []struct {
+		converters []*ID
+		s          string
+		wantCode   er.Code
+	}{
+		// One converter: its error is returned.
+		{converters: []*ID{converter}, s: "CNH M74 XCQ Y4Q H25", wantCode: er.ChecksumError},
+		// All converters fail the same way.
+		{converters: []*ID{converter, short}, s: "CNH M74 XCQ Y4Q H25", wantCode: er.ChecksumError},
+		// The converters fail differently.
+		{converters: []*ID{converter, hex}, s: "CNH M74 XCQ Y4Q H25", wantCode: er.NoMatchError},
+	} {
+		_, err := NewMultiDecoder(test.converters...).ToNr(test.s)
+		if err == nil || err.Code != test.wantCode {
+			t.Errorf("ToNr(%q) with %v converters = _,%v, want %v", test.s, len(test.converters), err, test.wantCode)
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package id
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/KarelKubat/hrid/er"
+)
+
+// Partial describes an ID that a user is still typing, see CheckPartial.
+type Partial struct {
+	Formatted string  // The input so far, normalised and re-grouped as ToString would.
+	Valid     bool    // True when every rune so far is acceptable.
+	Offending int     // The rune index in Formatted of the first unacceptable rune, or -1.
+	Remaining int     // The number of runes that remain to be typed to reach the expected length.
+	Complete  bool    // True once the expected length is reached.
+	Err       *er.Err // Why the input isn't Valid, or when Complete: the verdict of ToNr (nil when the ID is fine).
+	Checksums []int   // When Complete: the rune indexes in Formatted of the checksum runes, e.g. to highlight them.
+
+	cursor []int // For each rune position in the input, the rune position in Formatted.
+}
+
+// Cursor maps a rune position in the input of CheckPartial (e.g., where the caret of a text field is) to the rune
+// position in Formatted, so that the caret stays in place after re-grouping.
+func (p *Partial) Cursor(pos int) int {
+	if pos < 0 {
+		pos = 0
+	}
+	if pos >= len(p.cursor) {
+		pos = len(p.cursor) - 1
+	}
+	return p.cursor[pos]
+}
+
+// CheckPartial judges a string that a user is still typing. Whitespace is dropped, casing is folded (if so requested),
+// and the result is re-grouped. Runes that don't fit are reported, but kept in Formatted so that they can be shown.
+// For template-driven IDs, literal separators are inserted when they are not typed.
+func (id *ID) CheckPartial(s string) *Partial {
+	if id.opts.IgnoreCase {
+		s = strings.ToUpper(s)
+	}
+	p := &Partial{
+		Valid:     true,
+		Offending: -1,
+	}
+	out := []rune{}
+	typed := 0 // Number of typed runes that are kept, not counting literals of a template.
+	next := 0  // Next position in a template.
+
+	// reject is a helper to flag the first offending rune.
+	reject := func(err *er.Err) {
+		if p.Valid {
+			p.Valid = false
+			p.Offending = len(out)
+			p.Err = err
+		}
+	}
+
+	for _, r := range []rune(s) {
+		p.cursor = append(p.cursor, len(out))
+		if unicode.IsSpace(r) {
+			continue
+		}
+
+		// The version rune, if any, comes first.
+		if id.opts.Version != 0 && typed == 0 {
+			if r != id.opts.Version {
+				reject(er.Newf(er.UnknownVersionError, "ID doesn't start with version %v", string(id.opts.Version)))
+			}
+			out = append(out, r)
+			typed++
+			continue
+		}
+
+		if id.template != nil {
+			// Insert literals up to the next token, unless the user typed the literal.
+			consumed := false
+			for next < id.template.Len() {
+				lit, ok := id.template.Literal(next)
+				if !ok {
+					break
+				}
+				out = append(out, lit)
+				next++
+				if r == lit {
+					consumed = true
+					break
+				}
+			}
+			if consumed {
+				continue
+			}
+			if next >= id.template.Len() {
+				reject(er.Newf(er.IDTooLongError, "ID is longer than pattern %q", id.template.Pattern()))
+			} else if !id.template.Accepts(next, r) {
+				reject(er.Newf(er.NoSuchTokenError, "token %v not allowed at position %v", string(r), next+1))
+			}
+			out = append(out, r)
+			next++
+			typed++
+			continue
+		}
+
+		// Plain converter: insert a space between groups.
+		k := typed
+		if id.opts.Version != 0 {
+			k--
+		}
+		if id.opts.GroupSize > 0 && (k%id.opts.GroupSize == 0 && k > 0 || k == 0 && typed > 0) {
+			out = append(out, ' ')
+		}
+		if _, err := id.converter.Index(r); err != nil {
+			reject(err)
+		}
+		out = append(out, r)
+		typed++
+	}
+	p.cursor = append(p.cursor, len(out))
+	p.Formatted = string(out)
+
+	expected := id.expectedLen()
+	if typed < expected {
+		p.Remaining = expected - typed
+	}
+	p.Complete = typed >= expected
+	if p.Complete {
+		p.Checksums = id.checksumIndexes(out)
+	}
+	if p.Complete && p.Valid {
+		_, p.Err = id.ToNr(p.Formatted)
+	}
+	return p
+}
+
+// checksumIndexes is a helper that returns the rune indexes of the checksum runes in a formatted ID.
+func (id *ID) checksumIndexes(out []rune) []int {
+	indexes := []int{}
+	if id.template != nil {
+		offset := 0
+		if id.opts.Version != 0 {
+			offset = 1
+		}
+		for i := offset; i < len(out) && i-offset < id.template.Len(); i++ {
+			if id.template.Checksum(i - offset) {
+				indexes = append(indexes, i)
+			}
+		}
+		return indexes
+	}
+	for i := len(out) - 1; i >= 0 && len(indexes) < id.opts.ChecksumLen; i-- {
+		if out[i] != ' ' {
+			indexes = append([]int{i}, indexes...)
+		}
+	}
+	return indexes
+}
+
+// expectedLen is a helper that returns the minimal number of runes of a complete ID, excluding literal separators.
+func (id *ID) expectedLen() int {
+	n := 0
+	if id.opts.Version != 0 {
+		n++
+	}
+	if id.template != nil {
+		return n + id.template.Tokens()
+	}
+	padded := id.opts.StringLen + id.opts.ChecksumLen - 1
+	if shortest := 1 + id.opts.ChecksumLen; padded < shortest {
+		padded = shortest
+	}
+	return n + padded
+}
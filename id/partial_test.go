@@ -0,0 +1,127 @@
+package id
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/KarelKubat/hrid/er"
+)
+
+func TestCheckPartial(t *testing.T) {
+	full := ToString(12345)
+	for _, test := range []struct {
+		s             string
+		wantFormatted string
+		wantValid     bool
+		wantOffending int
+		wantRemaining int
+		wantComplete  bool
+		wantCode      er.Code
+		wantChecksums []int
+	}{
+		{
+			s:             "",
+			wantFormatted: "",
+			wantValid:     true,
+			wantOffending: -1,
+			wantRemaining: 15,
+		},
+		{
+			s:             "0000",
+			wantFormatted: "000 0",
+			wantValid:     true,
+			wantOffending: -1,
+			wantRemaining: 11,
+		},
+		{
+			s:             "00 0ab",
+			wantFormatted: "000 AB",
+			wantValid:     true,
+			wantOffending: -1,
+			wantRemaining: 10,
+		},
+		{
+			s:             "00i0",
+			wantFormatted: "00I 0",
+			wantOffending: 2,
+			wantRemaining: 11,
+			wantCode:      er.NoSuchTokenError,
+		},
+		{
+			s:             full,
+			wantFormatted: full,
+			wantValid:     true,
+			wantOffending: -1,
+			wantComplete:  true,
+			wantChecksums: []int{17, 18},
+		},
+		{
+			s:             full[:len(full)-1] + "0",
+			wantFormatted: full[:len(full)-1] + "0",
+			wantValid:     true,
+			wantOffending: -1,
+			wantComplete:  true,
+			wantCode:      er.ChecksumError,
+			wantChecksums: []int{17, 18},
+		},
+	} {
+		p := converter.CheckPartial(test.s)
+		gotCode := er.None
+		if p.Err != nil {
+			gotCode = p.Err.Code
+		}
+		if p.Formatted != test.wantFormatted || p.Valid != test.wantValid || p.Offending != test.wantOffending ||
+			p.Remaining != test.wantRemaining || p.Complete != test.wantComplete || gotCode != test.wantCode ||
+			fmt.Sprint(p.Checksums) != fmt.Sprint(test.wantChecksums) {
+			t.Errorf("CheckPartial(%q) = %+v, want formatted %q, valid %v, offending %v, remaining %v, complete %v, "+
+				"code %v, checksums %v",
+				test.s, p, test.wantFormatted, test.wantValid, test.wantOffending, test.wantRemaining, test.wantComplete,
+				test.wantCode, test.wantChecksums)
+		}
+	}
+}
+
+func TestCheckPartialTemplate(t *testing.T) {
+	id, err := New(&Opts{
+		Template:   "AA-9999-#",
+		IgnoreCase: true,
+	})
+	if err != nil {
+		t.Fatalf("New() = _,%v, need nil error", err)
+	}
+	for _, test := range []struct {
+		s             string
+		wantFormatted string
+		wantValid     bool
+		wantRemaining int
+		wantChecksums []int
+	}{
+		{s: "ab", wantFormatted: "AB", wantValid: true, wantRemaining: 5},
+		{s: "ab1", wantFormatted: "AB-1", wantValid: true, wantRemaining: 4},
+		{s: "ab-1", wantFormatted: "AB-1", wantValid: true, wantRemaining: 4},
+		{s: "a1", wantFormatted: "A1", wantRemaining: 5},
+		{s: "ab1234f", wantFormatted: "AB-1234-F", wantValid: true, wantChecksums: []int{8}},
+		{s: "ab1234ff", wantFormatted: "AB-1234-FF", wantChecksums: []int{8}},
+	} {
+		p := id.CheckPartial(test.s)
+		if p.Formatted != test.wantFormatted || p.Valid != test.wantValid || p.Remaining != test.wantRemaining ||
+			fmt.Sprint(p.Checksums) != fmt.Sprint(test.wantChecksums) {
+			t.Errorf("CheckPartial(%q) = %+v, want formatted %q, valid %v, remaining %v, checksums %v",
+				test.s, p, test.wantFormatted, test.wantValid, test.wantRemaining, test.wantChecksums)
+		}
+	}
+}
+
+func TestCursor(t *testing.T) {
+	p := converter.CheckPartial("0 0000")
+	// Input:     "0 0000"  Formatted: "000 00"
+	// Positions:  0123456              012345
+	for pos, want := range []int{0, 1, 1, 2, 3, 5, 6} {
+		if got := p.Cursor(pos); got != want {
+			t.Errorf("Cursor(%v) = %v, want %v", pos, got, want)
+		}
+	}
+	if got := p.Cursor(100); got != 6 {
+		t.Errorf("Cursor(100) = %v, want 6", got)
+	}
+}
@@ -0,0 +1,23 @@
+package id
+
+import (
+	"strings"
+
+	"github.com/KarelKubat/hrid/er"
+	"github.com/KarelKubat/hrid/qr"
+)
+
+// ToQR converts a uint64 to a QR code that holds the ID as ToString generates it, but without the spaces between
+// groups, which ToNr doesn't need. Template-driven IDs are encoded as-is. IDs of digits and uppercase letters encode
+// compactly, see package qr.
+func (id *ID) ToQR(n uint64, level qr.Level) (*qr.Code, *er.Err) {
+	runes, err := id.toRunes(n)
+	if err != nil {
+		return nil, err
+	}
+	s := string(runes)
+	if id.template == nil {
+		s = strings.ReplaceAll(s, " ", "")
+	}
+	return qr.Encode(s, level)
+}
@@ -0,0 +1,50 @@
+package id
+
+import (
+	"testing"
+
+	"github.com/KarelKubat/hrid/er"
+	"github.com/KarelKubat/hrid/qr"
+)
+
+func TestToQR(t *testing.T) {
+	tmpl, err := New(&Opts{Template: "AA-99"})
+	if err != nil {
+		t.Fatalf("New(AA-99) returned unexpected error %v", err)
+	}
+	for _, test := range []struct {
+		id          *ID
+		n           uint64
+		wantVersion int
+		wantCode    er.Code
+	}{
+		{
+			id:          converter,
+			n:           9999999999999999999, // CNHM74XCQY4QH24, 15 alphanumerics
+			wantVersion: 1,
+		},
+		{
+			id:          tmpl,
+			n:           12,
+			wantVersion: 1,
+		},
+		{
+			id:       tmpl,
+			n:        1000000,
+			wantCode: er.OutOfRangeError,
+		},
+	} {
+		c, err := test.id.ToQR(test.n, qr.M)
+		gotCode := er.None
+		if err != nil {
+			gotCode = err.Code
+		}
+		if gotCode != test.wantCode {
+			t.Errorf("ToQR(%v) = _,%v, want error code %v", test.n, err, test.wantCode)
+			continue
+		}
+		if err == nil && c.Version != test.wantVersion {
+			t.Errorf("ToQR(%v) = version %v, want %v", test.n, c.Version, test.wantVersion)
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package id
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/KarelKubat/hrid/er"
+)
+
+// Phonetic is a table to read IDs aloud in one language, e.g. by support staff on the phone.
+type Phonetic struct {
+	Words     map[rune]string // The spoken word for each rune, with letters in uppercase.
+	Pause     string          // Marker that's appended to the last word of a group, to pause before the next.
+	Lowercase string          // Word that precedes a lowercase letter, when an ID distinguishes casing.
+
+	// The below is only used for parsing transcripts.
+	Aliases map[string]rune // Alternative spellings and common mishearings of words.
+	Fillers []string        // Words that are ignored.
+}
+
+// PhoneticEnglish uses the NATO alphabet for letters and English words for digits.
+var PhoneticEnglish = &Phonetic{
+	Words: map[rune]string{
+		'0': "zero", '1': "one", '2': "two", '3': "three", '4': "four",
+		'5': "five", '6': "six", '7': "seven", '8': "eight", '9': "nine",
+		'A': "alfa", 'B': "bravo", 'C': "charlie", 'D': "delta", 'E': "echo",
+		'F': "foxtrot", 'G': "golf", 'H': "hotel", 'I': "india", 'J': "juliett",
+		'K': "kilo", 'L': "lima", 'M': "mike", 'N': "november", 'O': "oscar",
+		'P': "papa", 'Q': "quebec", 'R': "romeo", 'S': "sierra", 'T': "tango",
+		'U': "uniform", 'V': "victor", 'W': "whiskey", 'X': "x-ray", 'Y': "yankee",
+		'Z': "zulu",
+		'-': "dash", '.': "dot", '/': "slash",
+	},
+	Pause:     ",",
+	Lowercase: "small",
+	Aliases: map[string]rune{
+		"oh": '0', "o": '0', "zed": 'Z', "zee": 'Z', "won": '1', "to": '2', "too": '2', "tree": '3',
+		"for": '4', "fore": '4', "fower": '4', "fife": '5', "ate": '8', "niner": '9',
+		"alpha": 'A', "bee": 'B', "be": 'B', "see": 'C', "sea": 'C', "charley": 'C', "dee": 'D', "eff": 'F',
+		"gee": 'G', "aitch": 'H', "eye": 'I', "jay": 'J', "juliet": 'J', "kay": 'K', "keelo": 'K', "el": 'L',
+		"leema": 'L', "em": 'M', "en": 'N', "pea": 'P', "pee": 'P', "cue": 'Q', "queue": 'Q', "kebec": 'Q',
+		"are": 'R', "tea": 'T', "tee": 'T', "you": 'U', "vee": 'V', "whisky": 'W', "ex": 'X', "xray": 'X',
+		"x ray": 'X', "why": 'Y', "yankie": 'Y', "yanky": 'Y',
+	},
+	Fillers: []string{"uh", "um", "er", "and", "then", "pause", "space"},
+}
+
+// PhoneticDutch uses the Dutch spelling alphabet for letters and Dutch words for digits.
+var PhoneticDutch = &Phonetic{
+	Words: map[rune]string{
+		'0': "nul", '1': "een", '2': "twee", '3': "drie", '4': "vier",
+		'5': "vijf", '6': "zes", '7': "zeven", '8': "acht", '9': "negen",
+		'A': "anton", 'B': "bernard", 'C': "cornelis", 'D': "dirk", 'E': "eduard",
+		'F': "ferdinand", 'G': "gerard", 'H': "hendrik", 'I': "izaak", 'J': "johan",
+		'K': "karel", 'L': "lodewijk", 'M': "maria", 'N': "nico", 'O': "otto",
+		'P': "pieter", 'Q': "quirinus", 'R': "richard", 'S': "simon", 'T': "theodoor",
+		'U': "utrecht", 'V': "victor", 'W': "willem", 'X': "xantippe", 'Y': "ypsilon",
+		'Z': "zaandam",
+		'-': "streepje", '.': "punt", '/': "schuine streep",
+	},
+	Pause:     ",",
+	Lowercase: "klein",
+	Aliases: map[string]rune{
+		"één": '1', "eén": '1', "zeuven": '7', "tweeë": '2', "achte": '8',
+		"a": 'A', "bee": 'B', "cee": 'C', "dee": 'D', "ee": 'E', "ef": 'F', "gee": 'G', "ha": 'H',
+		"ie": 'I', "jee": 'J', "ka": 'K', "el": 'L', "em": 'M', "en": 'N', "oo": 'O', "pee": 'P',
+		"ku": 'Q', "er": 'R', "es": 'S', "tee": 'T', "uu": 'U', "vee": 'V', "wee": 'W', "iks": 'X',
+		"griekse ij": 'Y', "zet": 'Z', "xantippe": 'X', "quotiënt": 'Q',
+	},
+	Fillers: []string{"eh", "uh", "dan", "pauze", "spatie"},
+}
+
+// Phonetics maps language codes to the available tables. Callers may add their own.
+var Phonetics = map[string]*Phonetic{
+	"en": PhoneticEnglish,
+	"nl": PhoneticDutch,
+}
+
+// Spell converts an ID, as returned by ToString, to a transcript for reading it aloud. Whitespace between groups
+// becomes a pause. Runes that the table doesn't know are copied as-is.
+func (id *ID) Spell(s string, p *Phonetic) string {
+	groups := []string{}
+	for _, field := range strings.Fields(s) {
+		words := []string{}
+		for _, r := range field {
+			upper := unicode.ToUpper(r)
+			word, ok := p.Words[upper]
+			if !ok {
+				words = append(words, string(r))
+				continue
+			}
+			if !id.opts.IgnoreCase && upper != r && p.Lowercase != "" {
+				words = append(words, p.Lowercase)
+			}
+			words = append(words, word)
+		}
+		groups = append(groups, strings.Join(words, " "))
+	}
+	return strings.Join(groups, p.Pause+" ")
+}
+
+// ParseSpoken converts a transcript, such as "charlie november hotel seven four", to a number. Words are looked up in
+// the table of p and in its aliases; a word that's a single rune of the alphabet, or a row of digits, is taken as-is.
+// Fillers and punctuation are ignored. The words that couldn't be mapped are returned alongside the number and the
+// verdict of ToNr, which includes the checksum check.
+func (id *ID) ParseSpoken(transcript string, p *Phonetic) (uint64, []string, *er.Err) {
+	lookup := map[string]rune{}
+	for r, w := range p.Words {
+		lookup[w] = r
+	}
+	for w, r := range p.Aliases {
+		lookup[w] = r
+	}
+	fillers := map[string]bool{}
+	for _, w := range p.Fillers {
+		fillers[w] = true
+	}
+
+	// Dashes are kept in words, since they occur in e.g. "x-ray"; but "echo-foxtrot" is split.
+	words := []string{}
+	for _, w := range strings.FieldsFunc(strings.ToLower(transcript), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-'
+	}) {
+		if _, ok := lookup[w]; ok {
+			words = append(words, w)
+		} else {
+			words = append(words, strings.Split(w, "-")...)
+		}
+	}
+	runes := []rune{}
+	unmapped := []string{}
+	lower := false
+	for i := 0; i < len(words); i++ {
+		w := words[i]
+
+		// Try two words first, as in "x ray".
+		if i+1 < len(words) {
+			if r, ok := lookup[w+" "+words[i+1]]; ok {
+				runes = append(runes, id.spokenCase(r, &lower))
+				i++
+				continue
+			}
+		}
+		if r, ok := lookup[w]; ok {
+			runes = append(runes, id.spokenCase(r, &lower))
+			continue
+		}
+		switch {
+		case w == "":
+		case w == p.Lowercase:
+			lower = !id.opts.IgnoreCase
+		case fillers[w]:
+		case len([]rune(w)) == 1 && id.isToken([]rune(w)[0]):
+			runes = append(runes, id.spokenCase(unicode.ToUpper([]rune(w)[0]), &lower))
+		case strings.Trim(w, "0123456789") == "":
+			runes = append(runes, []rune(w)...)
+		default:
+			unmapped = append(unmapped, words[i])
+		}
+	}
+	n, err := id.ToNr(string(runes))
+	return n, unmapped, err
+}
+
+// spokenCase is a helper that lowercases a rune when the previous word said so.
+func (id *ID) spokenCase(r rune, lower *bool) rune {
+	if *lower {
+		*lower = false
+		return unicode.ToLower(r)
+	}
+	return r
+}
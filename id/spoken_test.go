@@ -0,0 +1,120 @@
+package id
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/KarelKubat/hrid/er"
+)
+
+func TestSpell(t *testing.T) {
+	caseSensitive, err := New(&Opts{Alphabet: "abcABC", GroupSize: 2})
+	if err != nil {
+		t.Fatalf("New() = _,%v, need nil error", err)
+	}
+	for _, test := range []struct {
+		id   *ID
+		s    string
+		p    *Phonetic
+		want string
+	}{
+		{
+			id:   converter,
+			s:    "CNH M74",
+			p:    PhoneticEnglish,
+			want: "charlie november hotel, mike seven four",
+		},
+		{
+			id:   converter,
+			s:    "cnh m74",
+			p:    PhoneticDutch,
+			want: "cornelis nico hendrik, maria zeven vier",
+		},
+		{
+			id:   converter,
+			s:    "AB-1+",
+			p:    PhoneticEnglish,
+			want: "alfa bravo dash one +",
+		},
+		{
+			id:   caseSensitive,
+			s:    "aA Bb",
+			p:    PhoneticEnglish,
+			want: "small alfa alfa, bravo small bravo",
+		},
+	} {
+		if got := test.id.Spell(test.s, test.p); got != test.want {
+			t.Errorf("Spell(%q) = %q, want %q", test.s, got, test.want)
+		}
+	}
+}
+
+func TestParseSpoken(t *testing.T) {
+	hex, err := New(&Opts{Alphabet: "0123456789ABCDEF", IgnoreCase: true, ChecksumLen: 1})
+	if err != nil {
+		t.Fatalf("New() = _,%v, need nil error", err)
+	}
+	// 0xBEEF is BEEF with checksum 11+14+14+15=54, 54%16=6.
+	for _, test := range []struct {
+		transcript   string
+		p            *Phonetic
+		wantNr       uint64
+		wantUnmapped []string
+		wantCode     er.Code
+	}{
+		{
+			transcript: "bravo echo echo foxtrot six",
+			p:          PhoneticEnglish,
+			wantNr:     0xBEEF,
+		},
+		{
+			transcript: "Bravo, echo. Uh, echo-foxtrot 6",
+			p:          PhoneticEnglish,
+			wantNr:     0xBEEF,
+		},
+		{
+			transcript: "bee echo e eff six",
+			p:          PhoneticEnglish,
+			wantNr:     0xBEEF,
+		},
+		{
+			transcript: "oh one won",
+			p:          PhoneticEnglish,
+			wantNr:     0x1,
+		},
+		{
+			transcript:   "bravo echo banana echo foxtrot six",
+			p:            PhoneticEnglish,
+			wantNr:       0xBEEF,
+			wantUnmapped: []string{"banana"},
+		},
+		{
+			transcript: "bravo echo echo foxtrot seven",
+			p:          PhoneticEnglish,
+			wantCode:   er.ChecksumError,
+		},
+		{
+			transcript: "bernard eduard eduard ferdinand zes",
+			p:          PhoneticDutch,
+			wantNr:     0xBEEF,
+		},
+	} {
+		gotNr, gotUnmapped, err := hex.ParseSpoken(test.transcript, test.p)
+		gotCode := er.None
+		if err != nil {
+			gotCode = err.Code
+		}
+		if gotNr != test.wantNr || gotCode != test.wantCode ||
+			strings.Join(gotUnmapped, ",") != strings.Join(test.wantUnmapped, ",") {
+			t.Errorf("ParseSpoken(%q) = %v,%v,%v, want %v,%v,%v",
+				test.transcript, gotNr, gotUnmapped, err, test.wantNr, test.wantUnmapped, test.wantCode)
+		}
+	}
+
+	// Round trip through Spell.
+	s := ToString(9999999999999999999)
+	n, unmapped, err := converter.ParseSpoken(converter.Spell(s, PhoneticEnglish), PhoneticEnglish)
+	if err != nil || len(unmapped) > 0 || n != 9999999999999999999 {
+		t.Errorf("ParseSpoken(Spell(%q)) = %v,%v,%v, want 9999999999999999999,[],nil", s, n, unmapped, err)
+	}
+}
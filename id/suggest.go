@@ -0,0 +1,104 @@
+package id
+
+import (
+	"strings"
+	"unicode"
+)
+
+// MaxSuggestions is the maximum number of suggestions that Suggest returns.
+const MaxSuggestions = 10
+
+// Lookalikes maps runes to the runes that they are easily mistaken for, when an ID is read or heard. Suggest tries
+// these first.
+var Lookalikes = map[rune]string{
+	'0': "OQD", 'O': "0QD", 'Q': "0O", 'D': "0OB",
+	'1': "ILJT7", 'I': "1LJ", 'L': "1I", 'J': "1I", 'T': "17", '7': "1T",
+	'2': "Z", 'Z': "2", '4': "A", 'A': "4", '5': "S", 'S': "5",
+	'6': "GB", 'G': "6C", 'C': "G", '8': "B", 'B': "8D",
+	'E': "F", 'F': "E", 'M': "N", 'N': "M", 'P': "R", 'R': "P",
+	'U': "V", 'V': "UY", 'Y': "V", 'K': "X", 'X': "K",
+}
+
+// Suggestion is a valid ID that's close to an invalid one, see Suggest.
+type Suggestion struct {
+	ID string // The ID, as ToString would generate it.
+	Nr uint64 // The number that the ID represents.
+}
+
+// Suggest returns valid IDs that are one typo away from s, the likeliest first: a rune that was mistaken for a
+// lookalike, two adjacent runes that were swapped, a rune too many, a rune too few, and any other rune that was
+// mistyped. Whitespace in s is ignored. At most MaxSuggestions are returned, each with a different number.
+func (id *ID) Suggest(s string) []Suggestion {
+	runes := []rune{}
+	for _, r := range s {
+		if !unicode.IsSpace(r) {
+			runes = append(runes, r)
+		}
+	}
+	if id.opts.IgnoreCase {
+		runes = []rune(strings.ToUpper(string(runes)))
+	}
+	valid := id.runes()
+
+	out := []Suggestion{}
+	seen := map[uint64]bool{}
+	// try is a helper that collects a candidate when it's valid, and returns false once there are enough.
+	try := func(candidate []rune) bool {
+		n, err := id.ToNr(string(candidate))
+		if err == nil && !seen[n] {
+			seen[n] = true
+			out = append(out, Suggestion{ID: id.ToString(n), Nr: n})
+		}
+		return len(out) < MaxSuggestions
+	}
+	// substitute is a helper that tries the runes of alternatives at each position.
+	substitute := func(alternatives func(r rune) string) bool {
+		for i, r := range runes {
+			for _, alt := range alternatives(r) {
+				if alt == r || !strings.ContainsRune(valid, alt) {
+					continue
+				}
+				candidate := append([]rune{}, runes...)
+				candidate[i] = alt
+				if !try(candidate) {
+					return false
+				}
+			}
+		}
+		return true
+	}
+
+	lookalikes := func(r rune) string {
+		alts := Lookalikes[unicode.ToUpper(r)]
+		if !id.opts.IgnoreCase {
+			alts = string(unicode.ToUpper(r)) + string(unicode.ToLower(r)) + alts + strings.ToLower(alts)
+		}
+		return alts
+	}
+	if !substitute(lookalikes) {
+		return out
+	}
+	for i := 0; i+1 < len(runes); i++ {
+		candidate := append([]rune{}, runes...)
+		candidate[i], candidate[i+1] = candidate[i+1], candidate[i]
+		if !try(candidate) {
+			return out
+		}
+	}
+	for i := range runes {
+		candidate := append(append([]rune{}, runes[:i]...), runes[i+1:]...)
+		if !try(candidate) {
+			return out
+		}
+	}
+	for i := 0; i <= len(runes); i++ {
+		for _, r := range valid {
+			candidate := append(append(append([]rune{}, runes[:i]...), r), runes[i:]...)
+			if !try(candidate) {
+				return out
+			}
+		}
+	}
+	substitute(func(rune) string { return valid })
+	return out
+}
@@ -0,0 +1,47 @@
+package id
+
+import (
+	"testing"
+)
+
+func TestSuggest(t *testing.T) {
+	for _, test := range []struct {
+		s         string
+		wantFirst uint64
+		wantOnly  bool
+	}{
+		// Wrong checksum rune.
+		{s: "CNH M74 XCQ Y4Q H25", wantFirst: 9999999999999999999, wantOnly: true},
+		// A lookalike.
+		{s: "000 O00 000 000 CCR", wantFirst: 12, wantOnly: true},
+		{s: "cnh m74 xcq y4q hz4", wantFirst: 9999999999999999999},
+		// A rune too few, and one too many.
+		{s: "CNHM74XCQY4QH2", wantFirst: 9999999999999999999, wantOnly: true},
+		{s: "CNH M74 XCQ Y4Q H244", wantFirst: 9999999999999999999},
+	} {
+		got := converter.Suggest(test.s)
+		if len(got) == 0 || got[0].Nr != test.wantFirst || got[0].ID != ToString(test.wantFirst) ||
+			test.wantOnly && len(got) != 1 {
+			t.Errorf("Suggest(%q) = %+v, want %v first (only: %v)", test.s, got, test.wantFirst, test.wantOnly)
+		}
+		seen := map[uint64]bool{}
+		for _, s := range got {
+			if n, err := ToNr(s.ID); err != nil || n != s.Nr || seen[n] {
+				t.Errorf("Suggest(%q) yields %+v: ToNr() = %v,%v, or it repeats", test.s, s, n, err)
+			}
+			seen[s.Nr] = true
+		}
+		if len(got) > MaxSuggestions {
+			t.Errorf("Suggest(%q) returned %v suggestions, want at most %v", test.s, len(got), MaxSuggestions)
+		}
+	}
+
+	// Casing matters when it isn't ignored.
+	hex, err := New(&Opts{Alphabet: "0123456789abcdef", ChecksumLen: 1})
+	if err != nil {
+		t.Fatalf("New() = _,%v, need nil error", err)
+	}
+	if got := hex.Suggest("a0A"); len(got) == 0 || got[0].ID != "a0a" {
+		t.Errorf("Suggest(a0A) = %+v, want a0a first", got)
+	}
+}
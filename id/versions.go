@@ -0,0 +1,61 @@
+package id
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/KarelKubat/hrid/er"
+)
+
+// Versions decodes IDs that start with a version rune, by dispatching to the converter that was registered for that
+// version. This allows changing the alphabet, length or checksumming over time, while older IDs remain valid.
+type Versions struct {
+	converters map[rune]*ID
+}
+
+// NewVersions returns a Versions for the given converters. Each converter must have a distinct, non-zero version.
+func NewVersions(converters ...*ID) (*Versions, *er.Err) {
+	v := &Versions{
+		converters: map[rune]*ID{},
+	}
+	for _, c := range converters {
+		if err := v.Register(c); err != nil {
+			return nil, err
+		}
+	}
+	return v, nil
+}
+
+// Register adds a converter. An error occurs when the converter has no version, or when its version is already taken.
+func (v *Versions) Register(c *ID) *er.Err {
+	version := c.Version()
+	if version == 0 {
+		return er.New(er.VersionError, "converter has no version")
+	}
+	if _, ok := v.converters[version]; ok {
+		return er.Newf(er.VersionError, "version %v repeats", string(version))
+	}
+	v.converters[version] = c
+	return nil
+}
+
+// ToNr converts a string to a uint64, using the converter that matches the version rune at the start of s. The
+// version is returned alongside the number.
+func (v *Versions) ToNr(s string) (uint64, rune, *er.Err) {
+	s = strings.TrimLeftFunc(s, unicode.IsSpace)
+	version, _ := utf8.DecodeRuneInString(s)
+	c, ok := v.converters[version]
+	if !ok {
+		// The registered version may be uppercased, when its converter ignores casing.
+		c, ok = v.converters[unicode.ToUpper(version)]
+		if !ok || !c.opts.IgnoreCase {
+			return 0, 0, er.Newf(er.UnknownVersionError, "ID %q has no known version", s)
+		}
+	}
+	n, err := c.ToNr(s)
+	if err != nil {
+		return 0, 0, err
+	}
+	return n, c.Version(), nil
+}
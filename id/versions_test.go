@@ -0,0 +1,103 @@
+package id
+
+import (
+	"testing"
+
+	"github.com/KarelKubat/hrid/er"
+)
+
+func TestVersionedID(t *testing.T) {
+	id, err := New(&Opts{
+		Alphabet:   "0123456789ABCDEF",
+		StringLen:  6,
+		IgnoreCase: true,
+		GroupSize:  2,
+		Version:    'v',
+	})
+	if err != nil {
+		t.Fatalf("New() = _,%v, need nil error", err)
+	}
+	if s := id.ToString(0xBEEF); s != "V 0B EE F" {
+		t.Errorf("id.ToString(0xBEEF) = %q, want %q", s, "V 0B EE F")
+	}
+	for _, test := range []struct {
+		s        string
+		wantNr   uint64
+		wantCode er.Code
+	}{
+		{s: "V 0B EE F", wantNr: 0xBEEF},
+		{s: " v0beef", wantNr: 0xBEEF},
+		{s: "W 0B EE F", wantCode: er.UnknownVersionError},
+		{s: "0B EE F", wantCode: er.UnknownVersionError},
+	} {
+		gotNr, err := id.ToNr(test.s)
+		gotCode := er.None
+		if err != nil {
+			gotCode = err.Code
+		}
+		if gotNr != test.wantNr || gotCode != test.wantCode {
+			t.Errorf("id.ToNr(%q) = %v,%v, want %v,%v", test.s, gotNr, err, test.wantNr, test.wantCode)
+		}
+	}
+
+	if _, err := New(&Opts{Alphabet: "01", Version: ' '}); err == nil || err.Code != er.VersionError {
+		t.Errorf("New() with a whitespace version = _,%v, want VersionError", err)
+	}
+}
+
+func TestVersions(t *testing.T) {
+	hex, err := New(&Opts{
+		Alphabet:   "0123456789ABCDEF",
+		StringLen:  8,
+		IgnoreCase: true,
+		Version:    'A',
+	})
+	if err != nil {
+		t.Fatalf("New(hex) = _,%v, need nil error", err)
+	}
+	def, err := New(&Opts{
+		Alphabet:    Alphabet,
+		StringLen:   StringLen,
+		IgnoreCase:  IgnoreCase,
+		GroupSize:   GroupSize,
+		ChecksumLen: ChecksumLen,
+		Version:     'B',
+	})
+	if err != nil {
+		t.Fatalf("New(default) = _,%v, need nil error", err)
+	}
+	versions, err := NewVersions(hex, def)
+	if err != nil {
+		t.Fatalf("NewVersions() = _,%v, need nil error", err)
+	}
+
+	for _, test := range []struct {
+		s           string
+		wantNr      uint64
+		wantVersion rune
+		wantCode    er.Code
+	}{
+		{s: hex.ToString(12345), wantNr: 12345, wantVersion: 'A'},
+		{s: def.ToString(12345), wantNr: 12345, wantVersion: 'B'},
+		{s: "a00003039", wantNr: 12345, wantVersion: 'A'},
+		{s: "C00003039", wantCode: er.UnknownVersionError},
+		{s: "B00003039", wantCode: er.ChecksumError},
+	} {
+		gotNr, gotVersion, err := versions.ToNr(test.s)
+		gotCode := er.None
+		if err != nil {
+			gotCode = err.Code
+		}
+		if gotNr != test.wantNr || gotVersion != test.wantVersion || gotCode != test.wantCode {
+			t.Errorf("versions.ToNr(%q) = %v,%q,%v, want %v,%q,%v",
+				test.s, gotNr, gotVersion, err, test.wantNr, test.wantVersion, test.wantCode)
+		}
+	}
+
+	if err := versions.Register(hex); err == nil || err.Code != er.VersionError {
+		t.Errorf("versions.Register() with a repeated version = %v, want VersionError", err)
+	}
+	if _, err := NewVersions(converter); err == nil || err.Code != er.VersionError {
+		t.Errorf("NewVersions() with an unversioned converter = _,%v, want VersionError", err)
+	}
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/KarelKubat/hrid/id"
+)
+
+// inspection describes a converter, as shown by the subcommand inspect. Numbers are decimal strings, as in result.
+type inspection struct {
+	Options   json.RawMessage `json:"options"`            // In the format of -config
+	Template  string          `json:"template,omitempty"` // The pattern, for template-driven IDs
+	Base      int             `json:"base,omitempty"`     // The number of tokens, for IDs without a template
+	Checksums int             `json:"checksums"`          // The number of checksum runes
+	Capacity  string          `json:"capacity"`           // The number of IDs
+	MaxNr     string          `json:"maxnr"`              // The largest number that can be converted
+	FirstID   string          `json:"firstid"`            // The ID of 0
+	LastID    string          `json:"lastid"`             // The ID of MaxNr
+	Length    string          `json:"length"`             // The number of runes, or a range such as "3-5"
+	Regexp    string          `json:"regexp"`             // As -emit regex prints it
+}
+
+// inspect describes the converter that is instantiated with opts.
+func inspect(c *id.ID, opts *id.Opts) *inspection {
+	ins := &inspection{
+		Options:  json.RawMessage(optsJSON(opts)),
+		Capacity: "18446744073709551616",
+		Regexp:   c.Regexp(),
+	}
+	maxNr := uint64(math.MaxUint64)
+	if t := c.Template(); t != nil {
+		ins.Template = t.Pattern()
+		ins.Checksums = t.Checksums()
+		if capacity, ok := t.Capacity(); ok {
+			ins.Capacity = strconv.FormatUint(capacity, 10)
+			maxNr = capacity - 1
+		}
+	} else {
+		ins.Base = utf8.RuneCountInString(opts.Alphabet)
+		ins.Checksums = opts.ChecksumLen
+	}
+	ins.MaxNr = strconv.FormatUint(maxNr, 10)
+	ins.FirstID = c.ToString(0)
+	ins.LastID = c.ToString(maxNr)
+	ins.Length = strconv.Itoa(utf8.RuneCountInString(ins.FirstID))
+	if l := utf8.RuneCountInString(ins.LastID); l != utf8.RuneCountInString(ins.FirstID) {
+		ins.Length = fmt.Sprintf("%v-%v", ins.Length, l)
+	}
+	return ins
+}
+
+// write writes an inspection in the format of -output: as lines of "key: value", or as a JSON object.
+func (ins *inspection) write(w io.Writer, format string) error {
+	if format != "text" {
+		js, _ := json.Marshal(ins)
+		_, err := fmt.Fprintf(w, "%s\n", js)
+		return err
+	}
+	lines := [][2]string{{"options", string(ins.Options)}}
+	if ins.Template != "" {
+		lines = append(lines, [2]string{"template", ins.Template})
+	} else {
+		lines = append(lines, [2]string{"base", strconv.Itoa(ins.Base)})
+	}
+	lines = append(lines,
+		[2]string{"checksums", strconv.Itoa(ins.Checksums)},
+		[2]string{"capacity", ins.Capacity + " IDs"},
+		[2]string{"numbers", "0 to " + ins.MaxNr},
+		[2]string{"first ID", ins.FirstID},
+		[2]string{"last ID", ins.LastID},
+		[2]string{"length", ins.Length + " runes"},
+		[2]string{"regexp", ins.Regexp},
+	)
+	for _, l := range lines {
+		if _, err := fmt.Fprintf(w, "%-10v %v\n", l[0]+":", l[1]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/KarelKubat/hrid/id"
+)
+
+func TestInspect(t *testing.T) {
+	for _, test := range []struct {
+		opts *id.Opts
+		want inspection
+	}{
+		{
+			opts: &id.Opts{Alphabet: "0123456789ABCDEF", StringLen: 4, ChecksumLen: 1},
+			want: inspection{
+				Base:      16,
+				Checksums: 1,
+				Capacity:  "18446744073709551616",
+				MaxNr:     "18446744073709551615",
+				FirstID:   "0000",
+				LastID:    "FFFFFFFFFFFFFFFF0",
+				Length:    "4-17",
+			},
+		},
+		{
+			opts: &id.Opts{Template: "AA-9999-#", IgnoreCase: true},
+			want: inspection{
+				Template:  "AA-9999-#",
+				Checksums: 1,
+				Capacity:  "4410000",
+				MaxNr:     "4409999",
+				FirstID:   "AA-0000-0",
+				LastID:    "YY-9999-E",
+				Length:    "9",
+			},
+		},
+	} {
+		c, err := id.New(test.opts)
+		if err != nil {
+			t.Fatalf("id.New(%+v) returned unexpected error %v", test.opts, err)
+		}
+		got := inspect(c, test.opts)
+		if got.Regexp != c.Regexp() || len(got.Options) == 0 {
+			t.Errorf("inspect(%+v) = %+v, want the regexp and options of the converter", test.opts, got)
+		}
+		got.Options, got.Regexp = nil, ""
+		if got.Template != test.want.Template || got.Base != test.want.Base || got.Checksums != test.want.Checksums ||
+			got.Capacity != test.want.Capacity || got.MaxNr != test.want.MaxNr || got.FirstID != test.want.FirstID ||
+			got.LastID != test.want.LastID || got.Length != test.want.Length {
+			t.Errorf("inspect(%+v) = %+v, want %+v", test.opts, *got, test.want)
+		}
+	}
+}
+
+func TestInspectionWrite(t *testing.T) {
+	ins := &inspection{
+		Options:  []byte(`{"schema":1}`),
+		Base:     16,
+		Capacity: "16",
+		MaxNr:    "15",
+		FirstID:  "0",
+		LastID:   "F",
+		Length:   "1",
+		Regexp:   "^[0-9A-F]$",
+	}
+	for _, test := range []struct {
+		format string
+		want   []string
+	}{
+		{
+			format: "text",
+			want:   []string{"options:   {\"schema\":1}\n", "base:      16\n", "numbers:   0 to 15\n", "length:    1 runes\n"},
+		},
+		{
+			format: "json",
+			want:   []string{`{"options":{"schema":1},"base":16,"checksums":0,"capacity":"16",`},
+		},
+	} {
+		var b bytes.Buffer
+		if err := ins.write(&b, test.format); err != nil {
+			t.Fatalf("write(%v) = %v, want nil", test.format, err)
+		}
+		for _, w := range test.want {
+			if !strings.Contains(b.String(), w) {
+				t.Errorf("write(%v) = %q, want it to contain %q", test.format, b.String(), w)
+			}
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/KarelKubat/hrid/er"
+)
+
+// outputs are the formats of -output.
+var outputs = map[string]bool{"text": true, "json": true, "jsonl": true}
+
+// numberError is the code of a result whose input isn't a number. Only the command parses numbers, so it's not an
+// er.Code.
+const numberError = "NumberError"
+
+// result is the outcome of converting one input. It is written as text, or by -output json or jsonl as an object.
+// Numbers are decimal strings, since a uint64 doesn't fit in a JavaScript number.
+type result struct {
+	Line      int          `json:"line,omitempty"`      // Line number of -batch input
+	Input     string       `json:"input"`               // As given
+	ID        string       `json:"id,omitempty"`        // Normalised: as the converter generates it
+	Nr        string       `json:"nr,omitempty"`        // The number
+	Checksum  string       `json:"checksum,omitempty"`  // The checksum runes of the ID
+	Spelling  string       `json:"spelling,omitempty"`  // With -spell
+	Ambiguous []match      `json:"ambiguous,omitempty"` // The converters that accept the ID, when there are several
+	Error     *resultError `json:"error,omitempty"`
+
+	text  string  // What -output text prints
+	what  string  // Context of the error, e.g. "not a valid ID"
+	cause *er.Err // The error, for the exit status; nil for a numberError
+}
+
+// match is a converter that accepts an ambiguous ID, with the number that it decodes.
+type match struct {
+	Converter int    `json:"converter"` // 0 for the converter of the flags, 1 for the first -profile etc.
+	Nr        string `json:"nr"`
+}
+
+// resultError is a failed conversion. The code is the name of an er.Code, or numberError.
+type resultError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// fail records an error in a result.
+func (r *result) fail(what string, err *er.Err) *result {
+	r.what = what
+	r.cause = err
+	r.Error = &resultError{
+		Code:    err.Code.String(),
+		Message: err.Msg,
+	}
+	return r
+}
+
+// failNumber records in a result that its input isn't a number, err being the error of strconv.ParseUint.
+func (r *result) failNumber(err error) *result {
+	msg := "not a decimal number"
+	if errors.Is(err, strconv.ErrRange) {
+		msg = "exceeds the largest number, 18446744073709551615"
+	}
+	r.what = "not a valid number"
+	r.Error = &resultError{
+		Code:    numberError,
+		Message: msg,
+	}
+	return r
+}
+
+// status returns the exit status for a result.
+func (r *result) status() int {
+	if r.cause == nil && r.Error != nil {
+		return exitInvalid
+	}
+	return exitCode(r.cause)
+}
+
+// err returns the error of a result in the form of -output text, or nil.
+func (r *result) err() error {
+	if r.Error == nil {
+		return nil
+	}
+	return fmt.Errorf("%v: %v: %v: %v", r.Input, r.what, r.Error.Code, r.Error.Message)
+}
+
+// output writes results in the format of -output: text (the ID or number of each result, or an empty line for a
+// failure), json (an array of objects) or jsonl (an object per line). The JSON array is written as the results come
+// in, and completed by close.
+type output struct {
+	w      io.Writer
+	format string
+	count  int
+}
+
+// write writes one result.
+func (o *output) write(r *result) error {
+	var err error
+	switch o.format {
+	case "json":
+		sep := ",\n"
+		if o.count == 0 {
+			sep = "[\n"
+		}
+		js, _ := json.Marshal(r)
+		_, err = fmt.Fprintf(o.w, "%v  %s", sep, js)
+	case "jsonl":
+		js, _ := json.Marshal(r)
+		_, err = fmt.Fprintf(o.w, "%s\n", js)
+	default:
+		_, err = fmt.Fprintln(o.w, r.text)
+	}
+	o.count++
+	return err
+}
+
+// close completes the output.
+func (o *output) close() error {
+	if o.format != "json" {
+		return nil
+	}
+	end := "\n]\n"
+	if o.count == 0 {
+		end = "[]\n"
+	}
+	_, err := io.WriteString(o.w, end)
+	return err
+}
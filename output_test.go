@@ -0,0 +1,103 @@
+package main
+
+import (
+	"bytes"
+	"strconv"
+	"testing"
+
+	"github.com/KarelKubat/hrid/er"
+)
+
+func TestOutput(t *testing.T) {
+	ok := &result{Input: "1", ID: "000 000 000 001 CCQ", Nr: "1", Checksum: "CQ", text: "000 000 000 001 CCQ"}
+	bad := (&result{Input: "x"}).fail("not a valid ID", er.New(er.ChecksumError, "wrong checksum"))
+	for _, test := range []struct {
+		format  string
+		results []*result
+		want    string
+	}{
+		{
+			format:  "text",
+			results: []*result{ok, bad},
+			want:    "000 000 000 001 CCQ\n\n",
+		},
+		{
+			format:  "json",
+			results: []*result{ok, bad},
+			want: "[\n" +
+				`  {"input":"1","id":"000 000 000 001 CCQ","nr":"1","checksum":"CQ"},` + "\n" +
+				`  {"input":"x","error":{"code":"ChecksumError","message":"wrong checksum"}}` + "\n]\n",
+		},
+		{
+			format: "json",
+			want:   "[]\n",
+		},
+		{
+			format:  "jsonl",
+			results: []*result{bad, ok},
+			want: `{"input":"x","error":{"code":"ChecksumError","message":"wrong checksum"}}` + "\n" +
+				`{"input":"1","id":"000 000 000 001 CCQ","nr":"1","checksum":"CQ"}` + "\n",
+		},
+		{
+			format: "jsonl",
+			want:   "",
+		},
+	} {
+		var b bytes.Buffer
+		o := &output{w: &b, format: test.format}
+		for _, r := range test.results {
+			if err := o.write(r); err != nil {
+				t.Fatalf("write(%+v) = %v, want nil", r, err)
+			}
+		}
+		if err := o.close(); err != nil {
+			t.Fatalf("close() = %v, want nil", err)
+		}
+		if got := b.String(); got != test.want {
+			t.Errorf("%v output = %q, want %q", test.format, got, test.want)
+		}
+	}
+}
+
+func TestResultErr(t *testing.T) {
+	_, syntax := strconv.ParseUint("x", 10, 64)
+	_, rng := strconv.ParseUint("18446744073709551616", 10, 64)
+	for _, test := range []struct {
+		r          *result
+		wantErr    string
+		wantStatus int
+	}{
+		{
+			r:          &result{Input: "1"},
+			wantStatus: exitOK,
+		},
+		{
+			r:          (&result{Input: "x"}).fail("not a valid ID", er.New(er.ChecksumError, "wrong checksum")),
+			wantErr:    "x: not a valid ID: ChecksumError: wrong checksum",
+			wantStatus: exitInvalid,
+		},
+		{
+			r:          (&result{Input: "x"}).failNumber(syntax),
+			wantErr:    "x: not a valid number: NumberError: not a decimal number",
+			wantStatus: exitInvalid,
+		},
+		{
+			r:          (&result{Input: "18446744073709551616"}).failNumber(rng),
+			wantErr:    "18446744073709551616: not a valid number: NumberError: exceeds the largest number, 18446744073709551615",
+			wantStatus: exitInvalid,
+		},
+		{
+			r:          (&result{Input: "x"}).fail("cannot convert", er.New(er.PatternError, "pattern")),
+			wantErr:    "x: cannot convert: PatternError: pattern",
+			wantStatus: exitConfig,
+		},
+	} {
+		gotErr := ""
+		if err := test.r.err(); err != nil {
+			gotErr = err.Error()
+		}
+		if gotErr != test.wantErr || test.r.status() != test.wantStatus {
+			t.Errorf("err(),status() of %+v = %q,%v, want %q,%v", test.r, gotErr, test.r.status(), test.wantErr, test.wantStatus)
+		}
+	}
+}
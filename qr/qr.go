@@ -0,0 +1,576 @@
+// Package qr is a small QR code encoder, so that IDs can be printed with a code that phones can scan. It supports
+// QR versions 1 to 10 (up to 57x57 modules) with numeric, alphanumeric or byte encoding, which is plenty for IDs. The
+// codes can be rendered as PNG, as SVG, or as Unicode half-block art for terminals.
+package qr
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"strings"
+
+	"github.com/KarelKubat/hrid/er"
+)
+
+// Level is the error correction level of a QR code.
+type Level int
+
+const (
+	L Level = iota // Recovers 7% of the data
+	M              // Recovers 15% of the data
+	Q              // Recovers 25% of the data
+	H              // Recovers 30% of the data
+)
+
+const (
+	// MaxVersion is the largest supported QR version.
+	MaxVersion = 10
+	// QuietZone is the number of light modules around a rendered QR code.
+	QuietZone = 4
+	// alphanumerics are the runes of the alphanumeric mode, their index is their value.
+	alphanumerics = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ $%*+-./:"
+)
+
+// blocks describes the error correction of a version and level: the number of EC codewords per block, and the
+// number of blocks and data codewords per block in up to two groups.
+type blocks struct {
+	ecLen             int
+	blocks1, dataLen1 int
+	blocks2, dataLen2 int
+}
+
+// blockTable is indexed by version-1 and level.
+var blockTable = [MaxVersion][4]blocks{
+	{{7, 1, 19, 0, 0}, {10, 1, 16, 0, 0}, {13, 1, 13, 0, 0}, {17, 1, 9, 0, 0}},
+	{{10, 1, 34, 0, 0}, {16, 1, 28, 0, 0}, {22, 1, 22, 0, 0}, {28, 1, 16, 0, 0}},
+	{{15, 1, 55, 0, 0}, {26, 1, 44, 0, 0}, {18, 2, 17, 0, 0}, {22, 2, 13, 0, 0}},
+	{{20, 1, 80, 0, 0}, {18, 2, 32, 0, 0}, {26, 2, 24, 0, 0}, {16, 4, 9, 0, 0}},
+	{{26, 1, 108, 0, 0}, {24, 2, 43, 0, 0}, {18, 2, 15, 2, 16}, {22, 2, 11, 2, 12}},
+	{{18, 2, 68, 0, 0}, {16, 4, 27, 0, 0}, {24, 4, 19, 0, 0}, {28, 4, 15, 0, 0}},
+	{{20, 2, 78, 0, 0}, {18, 4, 31, 0, 0}, {18, 2, 14, 4, 15}, {26, 4, 13, 1, 14}},
+	{{24, 2, 97, 0, 0}, {22, 2, 38, 2, 39}, {22, 4, 18, 2, 19}, {26, 4, 14, 2, 15}},
+	{{30, 2, 116, 0, 0}, {22, 3, 36, 2, 37}, {20, 4, 16, 4, 17}, {24, 4, 12, 4, 13}},
+	{{18, 2, 68, 2, 69}, {26, 4, 43, 1, 44}, {24, 6, 19, 2, 20}, {28, 6, 15, 2, 16}},
+}
+
+// alignment holds the centers of alignment patterns, indexed by version-1.
+var alignment = [MaxVersion][]int{
+	{}, {6, 18}, {6, 22}, {6, 26}, {6, 30}, {6, 34}, {6, 22, 38}, {6, 24, 42}, {6, 26, 46}, {6, 28, 50},
+}
+
+// formatLevel holds the bits of a level in the format information.
+var formatLevel = [4]int{1, 0, 3, 2}
+
+// dataLen returns the number of data codewords of a version and level.
+func (b blocks) dataLen() int {
+	return b.blocks1*b.dataLen1 + b.blocks2*b.dataLen2
+}
+
+// Code is an encoded QR code.
+type Code struct {
+	Version int // QR version, 1 to MaxVersion
+	Size    int // Number of modules per side
+
+	modules  [][]bool // True for dark modules, indexed by row and column
+	function [][]bool // True for modules that are part of function patterns
+}
+
+// Dark returns true when the module at row y and column x is dark.
+func (c *Code) Dark(x, y int) bool {
+	return c.modules[y][x]
+}
+
+// Encode returns the smallest QR code that holds text at the given error correction level. The most compact mode is
+// chosen that fits: numeric, alphanumeric (digits, uppercase letters, space and $%*+-./:) or bytes.
+func Encode(text string, level Level) (*Code, *er.Err) {
+	for version := 1; version <= MaxVersion; version++ {
+		b := blockTable[version-1][level]
+		bits := encodeData(text, version)
+		if len(bits.bits) > b.dataLen()*8 {
+			continue
+		}
+		c := &Code{
+			Version: version,
+			Size:    17 + 4*version,
+		}
+		c.modules = make([][]bool, c.Size)
+		c.function = make([][]bool, c.Size)
+		for i := range c.modules {
+			c.modules[i] = make([]bool, c.Size)
+			c.function[i] = make([]bool, c.Size)
+		}
+		c.drawFunctionPatterns()
+		c.drawCodewords(interleave(bits.codewords(b.dataLen()), b))
+		c.applyBestMask(level)
+		return c, nil
+	}
+	return nil, er.Newf(er.DataTooLongError, "%q doesn't fit in a QR code of version %v", text, MaxVersion)
+}
+
+// bitBuffer collects bits, most significant first.
+type bitBuffer struct {
+	bits []bool
+}
+
+// add appends the lowest n bits of v.
+func (b *bitBuffer) add(v, n int) {
+	for i := n - 1; i >= 0; i-- {
+		b.bits = append(b.bits, (v>>i)&1 == 1)
+	}
+}
+
+// codewords terminates and pads the bits to fill n codewords, and returns them.
+func (b *bitBuffer) codewords(n int) []byte {
+	for i := 0; i < 4 && len(b.bits) < n*8; i++ {
+		b.add(0, 1)
+	}
+	for len(b.bits)%8 != 0 {
+		b.add(0, 1)
+	}
+	for pad := 0xEC; len(b.bits) < n*8; pad ^= 0xEC ^ 0x11 {
+		b.add(pad, 8)
+	}
+	out := make([]byte, n)
+	for i, bit := range b.bits {
+		if bit {
+			out[i/8] |= 1 << (7 - i%8)
+		}
+	}
+	return out
+}
+
+// encodeData is a helper that encodes text in the most compact mode for a version.
+func encodeData(text string, version int) *bitBuffer {
+	// Lengths of the character count for numeric, alphanumeric and byte mode.
+	countBits := [3]int{10, 9, 8}
+	if version >= 10 {
+		countBits = [3]int{12, 11, 16}
+	}
+	b := &bitBuffer{}
+	switch {
+	case strings.Trim(text, "0123456789") == "":
+		b.add(1, 4)
+		b.add(len(text), countBits[0])
+		for i := 0; i < len(text); i += 3 {
+			end := i + 3
+			if end > len(text) {
+				end = len(text)
+			}
+			v := 0
+			for _, r := range text[i:end] {
+				v = v*10 + int(r-'0')
+			}
+			b.add(v, []int{0, 4, 7, 10}[end-i])
+		}
+	case strings.Trim(text, alphanumerics) == "":
+		b.add(2, 4)
+		b.add(len(text), countBits[1])
+		for i := 0; i < len(text); i += 2 {
+			if i+1 < len(text) {
+				b.add(strings.IndexByte(alphanumerics, text[i])*45+strings.IndexByte(alphanumerics, text[i+1]), 11)
+			} else {
+				b.add(strings.IndexByte(alphanumerics, text[i]), 6)
+			}
+		}
+	default:
+		b.add(4, 4)
+		b.add(len(text), countBits[2])
+		for i := 0; i < len(text); i++ {
+			b.add(int(text[i]), 8)
+		}
+	}
+	return b
+}
+
+// interleave is a helper that splits data into blocks, adds error correction to each, and interleaves the result.
+func interleave(data []byte, b blocks) []byte {
+	dataBlocks := [][]byte{}
+	ecBlocks := [][]byte{}
+	for i := 0; i < b.blocks1+b.blocks2; i++ {
+		n := b.dataLen1
+		if i >= b.blocks1 {
+			n = b.dataLen2
+		}
+		dataBlocks = append(dataBlocks, data[:n])
+		ecBlocks = append(ecBlocks, reedSolomon(data[:n], b.ecLen))
+		data = data[n:]
+	}
+	out := []byte{}
+	for _, group := range [][][]byte{dataBlocks, ecBlocks} {
+		for i := 0; ; i++ {
+			added := false
+			for _, block := range group {
+				if i < len(block) {
+					out = append(out, block[i])
+					added = true
+				}
+			}
+			if !added {
+				break
+			}
+		}
+	}
+	return out
+}
+
+// gfExp and gfLog are the exponent and logarithm tables of GF(256) with the polynomial x^8+x^4+x^3+x^2+1.
+var gfExp, gfLog [256]int
+
+func init() {
+	x := 1
+	for i := 0; i < 255; i++ {
+		gfExp[i] = x
+		gfLog[x] = i
+		x <<= 1
+		if x&0x100 != 0 {
+			x ^= 0x11D
+		}
+	}
+	gfExp[255] = gfExp[0]
+}
+
+// gfMul multiplies in GF(256).
+func gfMul(a, b int) int {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return gfExp[(gfLog[a]+gfLog[b])%255]
+}
+
+// reedSolomon is a helper that computes n error correction codewords for data.
+func reedSolomon(data []byte, n int) []byte {
+	// Generator polynomial (x - a^0)(x - a^1)...(x - a^(n-1)), highest coefficient first, leading 1 implied.
+	gen := make([]int, n)
+	gen[n-1] = 1
+	root := 1
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			gen[j] = gfMul(gen[j], root)
+			if j+1 < n {
+				gen[j] ^= gen[j+1]
+			}
+		}
+		root = gfMul(root, 2)
+	}
+
+	rem := make([]int, n)
+	for _, d := range data {
+		factor := int(d) ^ rem[0]
+		copy(rem, rem[1:])
+		rem[n-1] = 0
+		for j := range rem {
+			rem[j] ^= gfMul(gen[j], factor)
+		}
+	}
+	out := make([]byte, n)
+	for i, r := range rem {
+		out[i] = byte(r)
+	}
+	return out
+}
+
+// set is a helper that sets a function module at column x and row y.
+func (c *Code) set(x, y int, dark bool) {
+	c.modules[y][x] = dark
+	c.function[y][x] = true
+}
+
+// drawFunctionPatterns is a helper that draws the finder, timing and alignment patterns, and reserves the areas for
+// the format and version information.
+func (c *Code) drawFunctionPatterns() {
+	for i := 0; i < c.Size; i++ {
+		c.set(6, i, i%2 == 0)
+		c.set(i, 6, i%2 == 0)
+	}
+	for _, center := range [][2]int{{3, 3}, {c.Size - 4, 3}, {3, c.Size - 4}} {
+		for dy := -4; dy <= 4; dy++ {
+			for dx := -4; dx <= 4; dx++ {
+				x, y := center[0]+dx, center[1]+dy
+				if x >= 0 && x < c.Size && y >= 0 && y < c.Size {
+					dist := maxInt(abs(dx), abs(dy))
+					c.set(x, y, dist != 2 && dist != 4)
+				}
+			}
+		}
+	}
+	pos := alignment[c.Version-1]
+	for i, x := range pos {
+		for j, y := range pos {
+			if i == 0 && j == 0 || i == 0 && j == len(pos)-1 || i == len(pos)-1 && j == 0 {
+				continue // Overlaps a finder pattern
+			}
+			for dy := -2; dy <= 2; dy++ {
+				for dx := -2; dx <= 2; dx++ {
+					c.set(x+dx, y+dy, maxInt(abs(dx), abs(dy)) != 1)
+				}
+			}
+		}
+	}
+	c.drawFormat(0, 0) // Reserves the area, the real bits follow once the mask is known.
+	c.drawVersion()
+}
+
+// drawFormat is a helper that draws the format information: the error correction level and the mask.
+func (c *Code) drawFormat(levelBits, mask int) {
+	bits := formatBits(levelBits, mask)
+	bit := func(i int) bool {
+		return (bits>>i)&1 == 1
+	}
+
+	for i := 0; i <= 5; i++ {
+		c.set(8, i, bit(i))
+	}
+	c.set(8, 7, bit(6))
+	c.set(8, 8, bit(7))
+	c.set(7, 8, bit(8))
+	for i := 9; i < 15; i++ {
+		c.set(14-i, 8, bit(i))
+	}
+	for i := 0; i < 8; i++ {
+		c.set(c.Size-1-i, 8, bit(i))
+	}
+	for i := 8; i < 15; i++ {
+		c.set(8, c.Size-15+i, bit(i))
+	}
+	c.set(8, c.Size-8, true) // Always dark
+}
+
+// formatBits is a helper that returns the 15 bits of format information: the level and mask, their BCH code, and
+// a fixed XOR pattern.
+func formatBits(levelBits, mask int) int {
+	data := levelBits<<3 | mask
+	rem := data
+	for i := 0; i < 10; i++ {
+		rem = (rem << 1) ^ ((rem >> 9) * 0x537)
+	}
+	return (data<<10 | rem) ^ 0x5412
+}
+
+// drawVersion is a helper that draws the version information, which versions 7 and up have.
+func (c *Code) drawVersion() {
+	if c.Version < 7 {
+		return
+	}
+	rem := c.Version
+	for i := 0; i < 12; i++ {
+		rem = (rem << 1) ^ ((rem >> 11) * 0x1F25)
+	}
+	bits := c.Version<<12 | rem
+	for i := 0; i < 18; i++ {
+		dark := (bits>>i)&1 == 1
+		a, b := c.Size-11+i%3, i/3
+		c.set(a, b, dark)
+		c.set(b, a, dark)
+	}
+}
+
+// drawCodewords is a helper that places the codewords in a zigzag, upward and downward in columns of two modules,
+// starting at the bottom right and skipping the function patterns.
+func (c *Code) drawCodewords(codewords []byte) {
+	i := 0
+	for right := c.Size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5 // Skip the vertical timing pattern
+		}
+		for vert := 0; vert < c.Size; vert++ {
+			for j := 0; j < 2; j++ {
+				x := right - j
+				y := vert
+				if (right+1)&2 == 0 {
+					y = c.Size - 1 - vert // Upward
+				}
+				if !c.function[y][x] && i < len(codewords)*8 {
+					c.modules[y][x] = (codewords[i/8]>>(7-i%8))&1 == 1
+					i++
+				}
+			}
+		}
+	}
+}
+
+// masks are the eight mask patterns: a module at column x and row y is flipped when the mask returns true.
+var masks = [8]func(x, y int) bool{
+	func(x, y int) bool { return (x+y)%2 == 0 },
+	func(x, y int) bool { return y%2 == 0 },
+	func(x, y int) bool { return x%3 == 0 },
+	func(x, y int) bool { return (x+y)%3 == 0 },
+	func(x, y int) bool { return (x/3+y/2)%2 == 0 },
+	func(x, y int) bool { return x*y%2+x*y%3 == 0 },
+	func(x, y int) bool { return (x*y%2+x*y%3)%2 == 0 },
+	func(x, y int) bool { return ((x+y)%2+x*y%3)%2 == 0 },
+}
+
+// applyMask is a helper that flips the non-function modules that the mask selects. Applying it twice undoes it.
+func (c *Code) applyMask(mask int) {
+	for y := 0; y < c.Size; y++ {
+		for x := 0; x < c.Size; x++ {
+			if !c.function[y][x] && masks[mask](x, y) {
+				c.modules[y][x] = !c.modules[y][x]
+			}
+		}
+	}
+}
+
+// applyBestMask is a helper that applies the mask with the lowest penalty, and draws the format information.
+func (c *Code) applyBestMask(level Level) {
+	best, bestPenalty := 0, -1
+	for mask := range masks {
+		c.applyMask(mask)
+		c.drawFormat(formatLevel[level], mask)
+		if p := c.penalty(); bestPenalty < 0 || p < bestPenalty {
+			best, bestPenalty = mask, p
+		}
+		c.applyMask(mask)
+	}
+	c.applyMask(best)
+	c.drawFormat(formatLevel[level], best)
+}
+
+// penalty is a helper that scores how hard the code is to scan: long runs of one color, 2x2 blocks of one color,
+// patterns that resemble finders, and an unbalanced number of dark modules all count against it.
+func (c *Code) penalty() int {
+	p := 0
+	dark := 0
+	finderLike := []bool{true, false, true, true, true, false, true}
+	for a := 0; a < c.Size; a++ {
+		for _, horizontal := range []bool{true, false} {
+			at := func(b int) bool {
+				if horizontal {
+					return c.modules[a][b]
+				}
+				return c.modules[b][a]
+			}
+			run := 1
+			for b := 1; b <= c.Size; b++ {
+				if b < c.Size && at(b) == at(b-1) {
+					run++
+					continue
+				}
+				if run >= 5 {
+					p += 3 + run - 5
+				}
+				run = 1
+			}
+			for b := 0; b+7 <= c.Size; b++ {
+				match := true
+				for k, want := range finderLike {
+					if at(b+k) != want {
+						match = false
+						break
+					}
+				}
+				if !match {
+					continue
+				}
+				lightBefore, lightAfter := true, true
+				for k := 1; k <= 4; k++ {
+					if b-k >= 0 && at(b-k) {
+						lightBefore = false
+					}
+					if b+6+k < c.Size && at(b+6+k) {
+						lightAfter = false
+					}
+				}
+				if lightBefore || lightAfter {
+					p += 40
+				}
+			}
+		}
+	}
+	for y := 0; y < c.Size; y++ {
+		for x := 0; x < c.Size; x++ {
+			if c.modules[y][x] {
+				dark++
+			}
+			if x+1 < c.Size && y+1 < c.Size {
+				v := c.modules[y][x]
+				if c.modules[y][x+1] == v && c.modules[y+1][x] == v && c.modules[y+1][x+1] == v {
+					p += 3
+				}
+			}
+		}
+	}
+	percent := dark * 100 / (c.Size * c.Size)
+	p += abs(percent-50) / 5 * 10
+	return p
+}
+
+// PNG writes the code as a PNG image, where each module is scale by scale pixels. A quiet zone is added.
+func (c *Code) PNG(w io.Writer, scale int) error {
+	n := (c.Size + 2*QuietZone) * scale
+	img := image.NewGray(image.Rect(0, 0, n, n))
+	for py := 0; py < n; py++ {
+		for px := 0; px < n; px++ {
+			x, y := px/scale-QuietZone, py/scale-QuietZone
+			if x >= 0 && x < c.Size && y >= 0 && y < c.Size && c.modules[y][x] {
+				img.SetGray(px, py, color.Gray{Y: 0})
+			} else {
+				img.SetGray(px, py, color.Gray{Y: 255})
+			}
+		}
+	}
+	return png.Encode(w, img)
+}
+
+// SVG returns the code as an SVG image, where each module is scale by scale units. A quiet zone is added.
+func (c *Code) SVG(scale int) string {
+	n := (c.Size + 2*QuietZone) * scale
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n",
+		n, n, n, n)
+	fmt.Fprintf(&b, `<rect width="%d" height="%d" fill="#fff"/>`+"\n", n, n)
+	b.WriteString(`<path fill="#000" d="`)
+	for y := 0; y < c.Size; y++ {
+		for x := 0; x < c.Size; x++ {
+			if c.modules[y][x] {
+				fmt.Fprintf(&b, "M%d %dh%dv%dh-%dz", (x+QuietZone)*scale, (y+QuietZone)*scale, scale, scale, scale)
+			}
+		}
+	}
+	b.WriteString(`"/>` + "\n</svg>\n")
+	return b.String()
+}
+
+// Terminal returns the code as Unicode half-block art, two rows of modules per line of text. The art is drawn in
+// dark-on-light, so it scans best on terminals with a dark background where light runes show up light.
+func (c *Code) Terminal() string {
+	light := func(x, y int) bool {
+		return x < 0 || x >= c.Size || y < 0 || y >= c.Size || !c.modules[y][x]
+	}
+	var b strings.Builder
+	for y := -QuietZone; y < c.Size+QuietZone; y += 2 {
+		for x := -QuietZone; x < c.Size+QuietZone; x++ {
+			top, bottom := light(x, y), light(x, y+1)
+			switch {
+			case top && bottom:
+				b.WriteRune('█')
+			case top:
+				b.WriteRune('▀')
+			case bottom:
+				b.WriteRune('▄')
+			default:
+				b.WriteRune(' ')
+			}
+		}
+		b.WriteRune('\n')
+	}
+	return b.String()
+}
+
+// abs returns the absolute value of an int.
+func abs(a int) int {
+	if a < 0 {
+		return -a
+	}
+	return a
+}
+
+// maxInt returns the largest of two ints.
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,238 @@
+package qr
+
+import (
+	"bytes"
+	"image/png"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/KarelKubat/hrid/er"
+)
+
+func TestReedSolomon(t *testing.T) {
+	for _, test := range []struct {
+		data []byte
+		n    int
+		want []byte
+	}{
+		{
+			// HELLO WORLD, version 1-M
+			data: []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17},
+			n:    10,
+			want: []byte{196, 35, 39, 119, 235, 215, 231, 226, 93, 23},
+		},
+		{
+			// HELLO WORLD, version 1-Q
+			data: []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236},
+			n:    13,
+			want: []byte{168, 72, 22, 82, 217, 54, 156, 0, 46, 15, 180, 122, 16},
+		},
+	} {
+		if got := reedSolomon(test.data, test.n); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("reedSolomon(%v,%v) = %v, want %v", test.data, test.n, got, test.want)
+		}
+	}
+}
+
+func TestEncodeData(t *testing.T) {
+	for _, test := range []struct {
+		text    string
+		version int
+		n       int
+		want    []byte
+	}{
+		{
+			text:    "HELLO WORLD",
+			version: 1,
+			n:       13,
+			want:    []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236},
+		},
+		{
+			// Numeric mode: 0001, count 8 as 10 bits, then 012 345 67 as 10+10+7 bits.
+			text:    "01234567",
+			version: 1,
+			n:       8,
+			want:    []byte{0x10, 0x20, 0x0C, 0x56, 0x61, 0x80, 0xEC, 0x11},
+		},
+		{
+			// Byte mode: 0100, count 2, then 'a' and 'b'.
+			text:    "ab",
+			version: 1,
+			n:       5,
+			want:    []byte{0x40, 0x26, 0x16, 0x20, 0xEC},
+		},
+	} {
+		if got := encodeData(test.text, test.version).codewords(test.n); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("encodeData(%q,%v).codewords(%v) = %v, want %v", test.text, test.version, test.n, got, test.want)
+		}
+	}
+}
+
+func TestFormatBits(t *testing.T) {
+	for _, test := range []struct {
+		level Level
+		mask  int
+		want  int
+	}{
+		{L, 0, 0b111011111000100},
+		{M, 0, 0b101010000010010},
+		{Q, 0, 0b011010101011111},
+		{H, 0, 0b001011010001001},
+		{L, 2, 0b111110110101010},
+		{L, 7, 0b110100101110110},
+		{H, 7, 0b000100000111011},
+	} {
+		if got := formatBits(formatLevel[test.level], test.mask); got != test.want {
+			t.Errorf("formatBits(%v,%v) = %015b, want %015b", test.level, test.mask, got, test.want)
+		}
+	}
+}
+
+// readBack reads the format information and the codewords of a code, as a scanner would.
+func readBack(t *testing.T, c *Code, level Level) []byte {
+	format := 0
+	for i := 0; i <= 5; i++ {
+		if c.Dark(8, i) {
+			format |= 1 << i
+		}
+	}
+	for i, xy := range [][2]int{{8, 7}, {8, 8}, {7, 8}} {
+		if c.Dark(xy[0], xy[1]) {
+			format |= 1 << (6 + i)
+		}
+	}
+	for i := 9; i < 15; i++ {
+		if c.Dark(14-i, 8) {
+			format |= 1 << i
+		}
+	}
+	mask := -1
+	for m := range masks {
+		if formatBits(formatLevel[level], m) == format {
+			mask = m
+		}
+	}
+	if mask < 0 {
+		t.Fatalf("format bits %015b don't match level %v", format, level)
+	}
+
+	copy := &Code{Version: c.Version, Size: c.Size, modules: c.modules, function: c.function}
+	copy.applyMask(mask)
+	defer copy.applyMask(mask)
+	out := []byte{}
+	i := 0
+	for right := c.Size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vert := 0; vert < c.Size; vert++ {
+			for j := 0; j < 2; j++ {
+				x, y := right-j, vert
+				if (right+1)&2 == 0 {
+					y = c.Size - 1 - vert
+				}
+				if c.function[y][x] {
+					continue
+				}
+				if i%8 == 0 {
+					out = append(out, 0)
+				}
+				if c.modules[y][x] {
+					out[i/8] |= 1 << (7 - i%8)
+				}
+				i++
+			}
+		}
+	}
+	return out
+}
+
+func TestEncode(t *testing.T) {
+	for _, test := range []struct {
+		text        string
+		level       Level
+		wantVersion int
+		wantErr     er.Code
+	}{
+		{text: "HELLO WORLD", level: Q, wantVersion: 1},
+		{text: "01234567", level: H, wantVersion: 1},
+		{text: "CNH7 4KAB", level: M, wantVersion: 1},
+		{text: "https://example.com/id/CNH7-4KAB", level: M, wantVersion: 3},
+		{text: strings.Repeat("7", 340), level: L, wantVersion: 7},
+		{text: strings.Repeat("ab", 50), level: H, wantVersion: 10},
+		{text: strings.Repeat("ab", 200), level: L, wantErr: er.DataTooLongError},
+	} {
+		c, err := Encode(test.text, test.level)
+		switch {
+		case err != nil && err.Code != test.wantErr:
+			t.Errorf("Encode(%q,%v) = _,%v, want error %v", test.text, test.level, err, test.wantErr)
+			continue
+		case err == nil && test.wantErr != er.None:
+			t.Errorf("Encode(%q,%v) = _,nil, want error %v", test.text, test.level, test.wantErr)
+			continue
+		case err != nil:
+			continue
+		}
+		if c.Version != test.wantVersion || c.Size != 17+4*test.wantVersion {
+			t.Errorf("Encode(%q,%v) = version %v size %v, want version %v", test.text, test.level, c.Version, c.Size,
+				test.wantVersion)
+		}
+		b := blockTable[c.Version-1][test.level]
+		want := interleave(encodeData(test.text, c.Version).codewords(b.dataLen()), b)
+		got := readBack(t, c, test.level)
+		if !bytes.Equal(got[:len(want)], want) {
+			t.Errorf("Encode(%q,%v): codewords read back as %v, want %v", test.text, test.level, got, want)
+		}
+		free := 0
+		for y := range c.function {
+			for x := range c.function[y] {
+				if !c.function[y][x] {
+					free++
+				}
+			}
+		}
+		remainder := 0
+		if c.Version >= 2 && c.Version <= 6 {
+			remainder = 7
+		}
+		if free != len(want)*8+remainder {
+			t.Errorf("Encode(%q,%v): %v data modules, want %v", test.text, test.level, free, len(want)*8+remainder)
+		}
+	}
+}
+
+func TestRender(t *testing.T) {
+	c, err := Encode("CNH7 4KAB", M)
+	if err != nil {
+		t.Fatalf("Encode returned unexpected error %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := c.PNG(&buf, 3); err != nil {
+		t.Fatalf("PNG returned unexpected error %v", err)
+	}
+	img, err2 := png.Decode(&buf)
+	if err2 != nil {
+		t.Fatalf("png.Decode returned unexpected error %v", err2)
+	}
+	if want := (c.Size + 2*QuietZone) * 3; img.Bounds().Dx() != want {
+		t.Errorf("PNG is %v pixels wide, want %v", img.Bounds().Dx(), want)
+	}
+	if r, _, _, _ := img.At(QuietZone*3, QuietZone*3).RGBA(); r != 0 {
+		t.Errorf("PNG: top left module of the finder isn't dark")
+	}
+
+	svg := c.SVG(2)
+	if !strings.HasPrefix(svg, "<svg ") || !strings.HasSuffix(svg, "</svg>\n") {
+		t.Errorf("SVG = %q, want an svg element", svg)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(c.Terminal(), "\n"), "\n")
+	if want := (c.Size + 2*QuietZone + 1) / 2; len(lines) != want {
+		t.Errorf("Terminal has %v lines, want %v", len(lines), want)
+	}
+	if got := []rune(lines[QuietZone/2])[QuietZone]; got != ' ' {
+		t.Errorf("Terminal: top left of the finder is %q, want ' '", got)
+	}
+}
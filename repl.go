@@ -0,0 +1,305 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/KarelKubat/hrid/er"
+	"github.com/KarelKubat/hrid/id"
+)
+
+const (
+	// replHelp is what :help prints in an interactive session.
+	replHelp = `Type a number to see its ID, or an ID to see its number; what is typed is tried as both.
+Invalid IDs are shown with the offending rune or the checksum highlighted, and with suggestions.
+Commands:
+  :history - shows the entries of this session and their verdicts
+  :help    - shows this text
+  :quit    - stops, as does Ctrl-D
+`
+	// replSuggestions is the maximum number of suggestions that an interactive session shows.
+	replSuggestions = 5
+
+	// ANSI escape sequences to highlight verdicts.
+	ansiReset  = "\x1b[0m"
+	ansiRed    = "\x1b[1;31m"
+	ansiGreen  = "\x1b[32m"
+	ansiYellow = "\x1b[33m"
+	ansiDim    = "\x1b[2m"
+)
+
+// session is an interactive session of hrid -i. Each entry is tried as an ID and as a number, and what makes sense is
+// shown. The entries and their verdicts are kept as the history of the session.
+type session struct {
+	converters []*id.ID         // The first one encodes numbers, and judges and corrects IDs
+	decoder    *id.MultiDecoder // Decodes IDs, also of the -profile converters
+	color      bool             // When true, verdicts are highlighted
+	history    []entry
+}
+
+// entry is an entry of a session and its verdict.
+type entry struct {
+	input   string
+	verdict string
+}
+
+// newSession returns a session for converters, of which there must be at least one.
+func newSession(converters []*id.ID) *session {
+	return &session{
+		converters: converters,
+		decoder:    id.NewMultiDecoder(converters...),
+	}
+}
+
+// interactiveCmd runs a session on stdin and stdout, and returns the exit status. On a terminal, lines can be edited
+// and are validated while they are typed.
+func interactiveCmd(s *session) int {
+	restore, err := rawMode(int(os.Stdin.Fd()))
+	edit := err == nil
+	if edit {
+		// A panic unwinds through the deferred restore, but a signal would end hrid with the terminal in raw mode.
+		defer restore()
+		sigs := make(chan os.Signal, 1)
+		signal.Notify(sigs, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
+		defer signal.Stop(sigs)
+		done := make(chan struct{})
+		defer close(done)
+		go func() {
+			select {
+			case sig := <-sigs:
+				restore()
+				fmt.Fprintln(os.Stdout)
+				log.Printf("stopped by signal: %v", sig)
+				os.Exit(exitFailure)
+			case <-done:
+			}
+		}()
+		s.color = true
+	}
+	if err := s.run(os.Stdin, os.Stdout, edit); err != nil {
+		log.Print(err)
+		return exitFailure
+	}
+	return exitOK
+}
+
+// run reads entries from r and writes their verdicts to w, until r ends or :quit is given. When edit is true, r is a
+// terminal in raw mode, and lines are read with an editor.
+func (s *session) run(r io.Reader, w io.Writer, edit bool) error {
+	br := bufio.NewReader(r)
+	e := &editor{r: br, w: w, prompt: "hrid> ", live: s.live}
+	fmt.Fprintln(w, "Type numbers or IDs, :help for help, :quit or Ctrl-D to stop.")
+	for {
+		var line string
+		var err error
+		if edit {
+			line, err = e.readLine()
+		} else {
+			line, err = br.ReadString('\n')
+			if err == io.EOF && line != "" {
+				err = nil
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		line = strings.TrimSpace(line)
+		switch line {
+		case "":
+			continue
+		case ":quit", ":q":
+			return nil
+		case ":help":
+			fmt.Fprint(w, replHelp)
+			continue
+		case ":history":
+			for i, h := range s.history {
+				fmt.Fprintf(w, "%3d  %-25v %v\n", i+1, h.input, h.verdict)
+			}
+			continue
+		}
+		if strings.HasPrefix(line, ":") {
+			fmt.Fprintf(w, "%v: unknown command, try :help\n", line)
+			continue
+		}
+		out, verdict := s.eval(line)
+		fmt.Fprint(w, out)
+		s.history = append(s.history, entry{input: line, verdict: verdict})
+		if len(e.history) == 0 || e.history[len(e.history)-1] != line {
+			e.history = append(e.history, line)
+		}
+	}
+}
+
+// eval judges an entry as an ID and as a number. It returns what to show, and a one-line verdict for the history.
+func (s *session) eval(a string) (string, string) {
+	var b strings.Builder
+	verdicts := []string{}
+	line := func(key, value string) {
+		fmt.Fprintf(&b, "%-10v %v\n", key+":", value)
+	}
+
+	d, idErr := s.decoder.ToNr(a)
+	if idErr == nil {
+		c := s.converters[d.Index]
+		line("ID", s.paint(ansiGreen, c.ToString(d.Nr)))
+		line("number", strconv.FormatUint(d.Nr, 10))
+		verdict := "valid"
+		if cs, _ := c.ChecksumRunes(d.Nr); cs != "" {
+			verdict += ", checksum " + cs + " is right"
+		}
+		if d.Ambiguous {
+			verdict += fmt.Sprintf(", ambiguous: accepted by converters %v as numbers %v", d.Matches, d.Nrs)
+		} else if d.Index > 0 {
+			verdict += fmt.Sprintf(", by converter %v", d.Index)
+		}
+		line("verdict", verdict)
+		verdicts = append(verdicts, "valid ID of "+strconv.FormatUint(d.Nr, 10))
+	}
+
+	if n, err := strconv.ParseUint(a, 10, 64); err == nil {
+		if b.Len() > 0 {
+			b.WriteString("\n")
+		}
+		line("number", a)
+		c := s.converters[0]
+		if out, err := c.ToCheckedString(n); err != nil {
+			line("verdict", s.paint(ansiRed, "cannot convert: "+err.Error()))
+			verdicts = append(verdicts, "number that can't be converted")
+		} else {
+			line("ID", s.paint(ansiGreen, out))
+			cs, _ := c.ChecksumRunes(n)
+			line("verdict", "valid number, checksum "+cs)
+			verdicts = append(verdicts, "number of "+out)
+		}
+	}
+
+	if len(verdicts) > 0 {
+		return b.String(), strings.Join(verdicts, "; ")
+	}
+
+	p := s.converters[0].CheckPartial(a)
+	shown, marks := s.highlight(p)
+	line("ID", shown)
+	if marks != "" {
+		fmt.Fprintf(&b, "%-10v %v\n", "", marks)
+	}
+	verdict := s.verdict(p, idErr)
+	line("verdict", s.paint(ansiRed, "invalid, "+verdict))
+	for i, sg := range s.converters[0].Suggest(a) {
+		if i == replSuggestions {
+			break
+		}
+		key := ""
+		if i == 0 {
+			key = "maybe:"
+		}
+		fmt.Fprintf(&b, "%-10v %v = %v\n", key, sg.ID, sg.Nr)
+	}
+	return b.String(), "invalid ID, " + verdict
+}
+
+// verdict describes why an ID is invalid, including whether its checksum is right.
+func (s *session) verdict(p *id.Partial, err *er.Err) string {
+	switch {
+	case !p.Valid:
+		return fmt.Sprintf("checksum not verified: %v", p.Err)
+	case !p.Complete:
+		return fmt.Sprintf("checksum not verified: %v more runes expected", p.Remaining)
+	case p.Err != nil && p.Err.Code == er.ChecksumError:
+		return fmt.Sprintf("checksum is wrong: %v", p.Err)
+	case p.Err != nil:
+		return p.Err.Error()
+	case err != nil:
+		return err.Error()
+	default:
+		return "not accepted"
+	}
+}
+
+// highlight returns the formatted ID of p with the offending rune, or the checksum when it's wrong, highlighted.
+// Without color, marks underlines them with ^ and ~.
+func (s *session) highlight(p *id.Partial) (shown, marks string) {
+	runes := []rune(p.Formatted)
+	painted := map[int]string{}
+	if p.Offending >= 0 {
+		painted[p.Offending] = ansiRed
+	}
+	if p.Err != nil && p.Err.Code == er.ChecksumError {
+		for _, i := range p.Checksums {
+			painted[i] = ansiYellow
+		}
+	}
+	if len(painted) == 0 {
+		return p.Formatted, ""
+	}
+	var sb, mb strings.Builder
+	for i, r := range runes {
+		code, ok := painted[i]
+		sb.WriteString(s.paint(code, string(r)))
+		switch {
+		case !ok:
+			mb.WriteString(" ")
+		case code == ansiRed:
+			mb.WriteString("^")
+		default:
+			mb.WriteString("~")
+		}
+	}
+	if s.color {
+		return sb.String(), ""
+	}
+	return sb.String(), strings.TrimRight(mb.String(), " ")
+}
+
+// live renders a line that's being typed: the offending rune is highlighted, and the hint says what the line is so
+// far.
+func (s *session) live(line string) (string, string) {
+	a := strings.TrimSpace(line)
+	if a == "" || strings.HasPrefix(a, ":") {
+		return line, ""
+	}
+	if d, err := s.decoder.ToNr(a); err == nil {
+		return s.paint(ansiGreen, line), s.paint(ansiGreen, "valid ID of "+strconv.FormatUint(d.Nr, 10))
+	}
+	c := s.converters[0]
+	if n, err := strconv.ParseUint(a, 10, 64); err == nil {
+		if out, err := c.ToCheckedString(n); err == nil {
+			return line, s.paint(ansiDim, "number of "+out)
+		}
+	}
+	p := c.CheckPartial(line)
+	if p.Valid && !p.Complete {
+		return line, s.paint(ansiDim, fmt.Sprintf("%v more", p.Remaining))
+	}
+	shown := line
+	if p.Offending >= 0 {
+		// Find the typed rune that ended up as the offending rune of the formatted ID.
+		runes := []rune(line)
+		for i := range runes {
+			if p.Cursor(i+1)-1 == p.Offending {
+				shown = string(runes[:i]) + s.paint(ansiRed, string(runes[i])) + string(runes[i+1:])
+				break
+			}
+		}
+	}
+	return shown, s.paint(ansiRed, s.verdict(p, nil))
+}
+
+// paint is a helper that highlights text with an ANSI code, when the session uses color.
+func (s *session) paint(code, text string) string {
+	if !s.color || code == "" {
+		return text
+	}
+	return code + text + ansiReset
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/KarelKubat/hrid/id"
+)
+
+// testSession is a helper that returns a session with the default converter.
+func testSession(t *testing.T) *session {
+	c, err := id.New(&id.Opts{
+		Alphabet:    id.Alphabet,
+		StringLen:   id.StringLen,
+		IgnoreCase:  id.IgnoreCase,
+		GroupSize:   id.GroupSize,
+		ChecksumLen: id.ChecksumLen,
+	})
+	if err != nil {
+		t.Fatalf("id.New() = _,%v, want nil error", err)
+	}
+	return newSession([]*id.ID{c})
+}
+
+func TestSessionEval(t *testing.T) {
+	s := testSession(t)
+	for _, test := range []struct {
+		in          string
+		wantOut     []string
+		wantVerdict string
+	}{
+		{
+			in:          "cnh m74 xcq y4q h24",
+			wantOut:     []string{"ID:        CNH M74 XCQ Y4Q H24\n", "number:    9999999999999999999\n", "checksum 24 is right"},
+			wantVerdict: "valid ID of 9999999999999999999",
+		},
+		{
+			in:          "12",
+			wantOut:     []string{"number:    12\n", "ID:        000 000 000 000 CCR\n"},
+			wantVerdict: "number of 000 000 000 000 CCR",
+		},
+		{
+			in: "CNH M74 XCQ Y4Q H25",
+			wantOut: []string{
+				"ID:        CNH M74 XCQ Y4Q H25\n" +
+					"                            ~~\n",
+				"checksum is wrong",
+				"maybe:     CNH M74 XCQ Y4Q H24 = 9999999999999999999\n",
+			},
+			wantVerdict: "invalid ID, checksum is wrong",
+		},
+		{
+			in: "00i0",
+			wantOut: []string{
+				"ID:        00I 0\n" +
+					"             ^\n",
+				"NoSuchTokenError",
+			},
+			wantVerdict: "invalid ID, checksum not verified",
+		},
+		{
+			in:          "cnh m74",
+			wantOut:     []string{"9 more runes expected"},
+			wantVerdict: "invalid ID, checksum not verified: 9 more runes expected",
+		},
+	} {
+		out, verdict := s.eval(test.in)
+		for _, w := range test.wantOut {
+			if !strings.Contains(out, w) {
+				t.Errorf("eval(%q) = %q,_, want it to contain %q", test.in, out, w)
+			}
+		}
+		if !strings.HasPrefix(verdict, test.wantVerdict) {
+			t.Errorf("eval(%q) = _,%q, want it to start with %q", test.in, verdict, test.wantVerdict)
+		}
+	}
+}
+
+func TestSessionLive(t *testing.T) {
+	s := testSession(t)
+	s.color = true
+	for _, test := range []struct {
+		line      string
+		wantShown string
+		wantHint  string
+	}{
+		{line: "", wantShown: "", wantHint: ""},
+		{line: ":hist", wantShown: ":hist", wantHint: ""},
+		{line: "00 i0", wantShown: "00 " + ansiRed + "i" + ansiReset + "0", wantHint: "NoSuchTokenError"},
+		{line: "cnh", wantShown: "cnh", wantHint: "12 more"},
+		{line: "12", wantShown: "12", wantHint: "number of 000 000 000 000 CCR"},
+		{line: "cnhm74xcqy4qh24", wantShown: ansiGreen + "cnhm74xcqy4qh24", wantHint: "valid ID of 9999999999999999999"},
+		{line: "cnhm74xcqy4qh25", wantShown: "cnhm74xcqy4qh25", wantHint: "checksum is wrong"},
+	} {
+		shown, hint := s.live(test.line)
+		if !strings.HasPrefix(shown, test.wantShown) || !strings.Contains(hint, test.wantHint) ||
+			(hint == "") != (test.wantHint == "") {
+			t.Errorf("live(%q) = %q,%q, want %q,%q", test.line, shown, hint, test.wantShown, test.wantHint)
+		}
+	}
+}
+
+func TestSessionRun(t *testing.T) {
+	s := testSession(t)
+	in := "12\n\n  cnh m74 xcq y4q h25 \n:history\n:what\n:quit\n13\n"
+	var out bytes.Buffer
+	if err := s.run(strings.NewReader(in), &out, false); err != nil {
+		t.Fatalf("run() = %v, want nil error", err)
+	}
+	for _, w := range []string{
+		"  1  12                        number of 000 000 000 000 CCR\n",
+		"  2  cnh m74 xcq y4q h25       invalid ID, checksum is wrong",
+		":what: unknown command",
+	} {
+		if !strings.Contains(out.String(), w) {
+			t.Errorf("run() wrote %q, want it to contain %q", out.String(), w)
+		}
+	}
+	if len(s.history) != 2 {
+		t.Errorf("run() kept history %+v, want 2 entries, and nothing after :quit", s.history)
+	}
+}
@@ -0,0 +1,36 @@
+//go:build linux
+
+package main
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// rawMode switches the terminal of fd to raw mode, so that the line editor of -i sees every key as it's pressed. It
+// returns a function that restores the terminal, or an error when fd isn't a terminal. Output processing stays on, so
+// that a newline still starts a new line.
+func rawMode(fd int) (func(), error) {
+	var old syscall.Termios
+	if err := termios(fd, syscall.TCGETS, &old); err != nil {
+		return nil, err
+	}
+	raw := old
+	raw.Iflag &^= syscall.BRKINT | syscall.ICRNL | syscall.INPCK | syscall.ISTRIP | syscall.IXON
+	raw.Lflag &^= syscall.ECHO | syscall.ICANON | syscall.IEXTEN | syscall.ISIG
+	raw.Cc[syscall.VMIN] = 1
+	raw.Cc[syscall.VTIME] = 0
+	if err := termios(fd, syscall.TCSETS, &raw); err != nil {
+		return nil, err
+	}
+	return func() { termios(fd, syscall.TCSETS, &old) }, nil
+}
+
+// termios is a helper that gets or sets the attributes of a terminal.
+func termios(fd int, request uintptr, t *syscall.Termios) error {
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(fd), request, uintptr(unsafe.Pointer(t)))
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
@@ -0,0 +1,21 @@
+//go:build linux
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRawMode(t *testing.T) {
+	f, err := os.Create(filepath.Join(t.TempDir(), "not-a-terminal"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if restore, err := rawMode(int(f.Fd())); err == nil {
+		restore()
+		t.Errorf("rawMode() of a file = _,nil, want error")
+	}
+}
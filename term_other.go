@@ -0,0 +1,10 @@
+//go:build !linux
+
+package main
+
+import "errors"
+
+// rawMode isn't supported on this platform, so -i reads lines as the terminal delivers them.
+func rawMode(fd int) (func(), error) {
+	return nil, errors.New("raw terminal mode is only supported on Linux")
+}
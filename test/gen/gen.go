@@ -0,0 +1,11 @@
+// Package gen is an example of generated converters: ids.go is generated from hrid.json, which holds the defaults of
+// package id, and ids_test.go proves that both are equivalent. Orders use a versioned hex converter without grouping.
+// ids.ts and orders.ts are the same converters in TypeScript, and ids.sql and orders.sql in PL/pgSQL.
+package gen
+
+//go:generate go run ../../cmd/hrid-gen -config hrid.json -output ids.go
+//go:generate go run ../../cmd/hrid-gen -config orders.json -output orders.go -prefix Order
+//go:generate go run ../../cmd/hrid-gen -config hrid.json -language typescript -output ids.ts
+//go:generate go run ../../cmd/hrid-gen -config orders.json -language typescript -output orders.ts
+//go:generate go run ../../cmd/hrid-gen -config hrid.json -language postgresql -output ids.sql
+//go:generate go run ../../cmd/hrid-gen -config orders.json -language postgresql -output orders.sql -prefix Order
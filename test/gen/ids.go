@@ -0,0 +1,160 @@
+// Code generated by hrid-gen; DO NOT EDIT.
+
+// This converter is equivalent to package github.com/KarelKubat/hrid/id with the options:
+// {"schema":1,"alphabet":"0123456789ABCDEFGHKLMNPQRTUVWXY","length":14,"ignorecase":true,"groupsize":3,"checksum":2}
+
+package gen
+
+import (
+	"errors"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Alphabet holds the tokens of IDs, in the order of their values.
+const Alphabet = "0123456789ABCDEFGHKLMNPQRTUVWXY"
+
+// Errors of ToNr.
+var (
+	ErrTooShort    = errors.New("ID too short")
+	ErrNoSuchToken = errors.New("ID has a token that's not in the alphabet")
+	ErrChecksum    = errors.New("checksum error")
+)
+
+// tokenIndex holds the value of each ASCII rune, or -1 for runes that aren't tokens.
+var tokenIndex = [128]int8{
+	-1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1,
+	-1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1,
+	-1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1,
+	0, 1, 2, 3, 4, 5, 6, 7, 8, 9, -1, -1, -1, -1, -1, -1,
+	-1, 10, 11, 12, 13, 14, 15, 16, 17, -1, -1, 18, 19, 20, 21, -1,
+	22, 23, 24, -1, 25, 26, 27, 28, 29, 30, -1, -1, -1, -1, -1, -1,
+	-1, 10, 11, 12, 13, 14, 15, 16, 17, -1, -1, 18, 19, 20, 21, -1,
+	22, 23, 24, -1, 25, 26, 27, 28, 29, 30, -1, -1, -1, -1, -1, -1,
+}
+
+// ToString converts a uint64 to an ID.
+func ToString(n uint64) string {
+	var buf [15]byte
+	for i := range buf {
+		buf[i] = '0'
+	}
+
+	// Digits, least significant first.
+	var v uint64
+	var sum uint64
+	v = n % 31
+	buf[12] = Alphabet[v]
+	sum += v
+	n /= 31
+	v = n % 31
+	buf[11] = Alphabet[v]
+	sum += v
+	n /= 31
+	v = n % 31
+	buf[10] = Alphabet[v]
+	sum += v
+	n /= 31
+	v = n % 31
+	buf[9] = Alphabet[v]
+	sum += v
+	n /= 31
+	v = n % 31
+	buf[8] = Alphabet[v]
+	sum += v
+	n /= 31
+	v = n % 31
+	buf[7] = Alphabet[v]
+	sum += v
+	n /= 31
+	v = n % 31
+	buf[6] = Alphabet[v]
+	sum += v
+	n /= 31
+	v = n % 31
+	buf[5] = Alphabet[v]
+	sum += v
+	n /= 31
+	v = n % 31
+	buf[4] = Alphabet[v]
+	sum += v
+	n /= 31
+	v = n % 31
+	buf[3] = Alphabet[v]
+	sum += v
+	n /= 31
+	v = n % 31
+	buf[2] = Alphabet[v]
+	sum += v
+	n /= 31
+	v = n % 31
+	buf[1] = Alphabet[v]
+	sum += v
+	n /= 31
+	v = n % 31
+	buf[0] = Alphabet[v]
+	sum += v
+	n /= 31
+
+	// Checksums, each over all earlier runes.
+	v = sum % 31
+	buf[13] = Alphabet[v]
+	sum += v
+	v = sum % 31
+	buf[14] = Alphabet[v]
+	sum += v
+
+	// Split into groups.
+	out := make([]byte, 0, 2+len(buf)*2)
+	for i := 0; i < len(buf); i += 3 {
+		if i > 0 {
+			out = append(out, ' ')
+		}
+		end := i + 3
+		if end > len(buf) {
+			end = len(buf)
+		}
+		out = append(out, buf[i:end]...)
+	}
+	return string(out)
+}
+
+// ToNr converts an ID to a uint64.
+func ToNr(s string) (uint64, error) {
+
+	var stack [15]uint8
+	values := stack[:0]
+	for _, r := range s {
+		if unicode.IsSpace(r) {
+			continue
+		}
+		if r >= utf8.RuneSelf {
+			r = unicode.ToUpper(r)
+		}
+		if r >= 128 || tokenIndex[r] < 0 {
+			return 0, ErrNoSuchToken
+		}
+		values = append(values, uint8(tokenIndex[r]))
+	}
+	if len(values) <= 2 {
+		return 0, ErrTooShort
+	}
+	digits := len(values) - 2
+
+	sum := uint64(0)
+	for _, v := range values[:digits] {
+		sum = (sum + uint64(v)) % 31
+	}
+	for _, v := range values[digits:] {
+		if uint64(v) != sum {
+			return 0, ErrChecksum
+		}
+		sum = (sum + uint64(v)) % 31
+	}
+
+	out := uint64(0)
+	for _, v := range values[:digits] {
+		out = out*31 + uint64(v)
+	}
+	return out, nil
+}
@@ -0,0 +1,52 @@
+// Code generated by hrid-gen; DO NOT EDIT.
+
+package gen
+
+import (
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/KarelKubat/hrid/id"
+)
+
+func TestOrderEquivalence(t *testing.T) {
+	runtime, err := id.New(&id.Opts{Alphabet: "0123456789abcdef", StringLen: 8, IgnoreCase: false, GroupSize: 0, ChecksumLen: 1, Version: 'v'})
+	if err != nil {
+		t.Fatalf("id.New() returned unexpected error %v", err)
+	}
+	rnd := rand.New(rand.NewSource(1))
+	nrs := []uint64{0, 1, math.MaxUint64}
+	for i := 0; i < 10000; i++ {
+		nrs = append(nrs, rnd.Uint64()>>rnd.Intn(64))
+	}
+	for _, n := range nrs {
+		want := runtime.ToString(n)
+		if got := OrderToString(n); got != want {
+			t.Fatalf("OrderToString(%v) = %q, want %q", n, got, want)
+		}
+
+		// Mutate one rune, to try invalid tokens and checksum errors.
+		mutated := []rune(want)
+		mutated[rnd.Intn(len(mutated))] = rune(OrderAlphabet[rnd.Intn(len(OrderAlphabet))])
+		garbled := []rune(want)
+		garbled[rnd.Intn(len(garbled))] = rune(rnd.Intn(0x300))
+
+		for _, s := range []string{
+			want,
+			strings.ToLower(want),
+			strings.ReplaceAll(want, " ", ""),
+			" " + want + "\t",
+			string(mutated),
+			string(garbled),
+			want[:len(want)/2],
+		} {
+			wantNr, wantErr := runtime.ToNr(s)
+			gotNr, gotErr := OrderToNr(s)
+			if (gotErr != nil) != (wantErr != nil) || wantErr == nil && gotNr != wantNr {
+				t.Fatalf("OrderToNr(%q) = %v,%v, want %v,%v", s, gotNr, gotErr, wantNr, wantErr)
+			}
+		}
+	}
+}
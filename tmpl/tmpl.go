@@ -0,0 +1,326 @@
+// Package tmpl implements mixed-radix conversions that are driven by a pattern, such as "AA-9999-A" for "two letters,
+// four digits, one letter". Each position of the pattern has its own alphabet, other runes are literal separators.
+//
+// The pattern syntax is:
+//
+//	A       a letter, any of Letters
+//	9       a digit, any of Digits
+//	X       a letter or digit, any of Alphanumerics
+//	[...]   any of the runes between the brackets, which are the alphabet for this position
+//	#       a checksum rune, taken from Alphanumerics
+//	#[...]  a checksum rune, taken from the runes between the brackets
+//	\c      the literal rune c
+//	c       any other rune is a literal separator
+package tmpl
+
+import (
+	"math/bits"
+	"strings"
+	"unicode"
+
+	"github.com/KarelKubat/hrid/er"
+)
+
+const (
+	// Letters is the alphabet for an A in a pattern. It lacks letters that resemble digits.
+	Letters = "ABCDEFGHKLMNPQRTUVWXY"
+	// Digits is the alphabet for a 9 in a pattern.
+	Digits = "0123456789"
+	// Alphanumerics is the alphabet for an X or a # in a pattern.
+	Alphanumerics = Digits + Letters
+)
+
+// position is one rune of a template: either a literal, or a token from its own alphabet.
+type position struct {
+	literal    rune         // Literal rune, when alphabet is nil
+	alphabet   []rune       // Runes that may occur at this position
+	tokenIndex map[rune]int // Value of each rune in alphabet
+	checksum   bool         // When true, the position is a checksum over all earlier positions
+}
+
+// Template is the receiver that implements ToString and ToNr.
+type Template struct {
+	pattern    string
+	ignoreCase bool
+	positions  []position
+}
+
+// New returns a Template for a pattern. When ignoreCase is true, all alphabets and literals are uppercased, and so is
+// the input of ToNr.
+func New(pattern string, ignoreCase bool) (*Template, *er.Err) {
+	t := &Template{
+		pattern:    pattern,
+		ignoreCase: ignoreCase,
+	}
+	runes := []rune(pattern)
+	for i := 0; i < len(runes); i++ {
+		var p position
+		switch r := runes[i]; r {
+		case 'A':
+			p.alphabet = []rune(Letters)
+		case '9':
+			p.alphabet = []rune(Digits)
+		case 'X':
+			p.alphabet = []rune(Alphanumerics)
+		case '[':
+			end, alphabet, err := bracketed(runes, i)
+			if err != nil {
+				return nil, err
+			}
+			i = end
+			p.alphabet = alphabet
+		case '#':
+			p.checksum = true
+			p.alphabet = []rune(Alphanumerics)
+			if i+1 < len(runes) && runes[i+1] == '[' {
+				end, alphabet, err := bracketed(runes, i+1)
+				if err != nil {
+					return nil, err
+				}
+				i = end
+				p.alphabet = alphabet
+			}
+		case '\\':
+			if i+1 == len(runes) {
+				return nil, er.Newf(er.PatternError, "pattern %q ends in a backslash", pattern)
+			}
+			i++
+			p.literal = runes[i]
+		default:
+			p.literal = r
+		}
+		if err := p.index(ignoreCase); err != nil {
+			return nil, err
+		}
+		t.positions = append(t.positions, p)
+	}
+
+	if t.Tokens() == 0 {
+		return nil, er.Newf(er.PatternError, "pattern %q has no positions for tokens", pattern)
+	}
+	if t.positions[0].checksum || t.Tokens() == t.Checksums() {
+		return nil, er.Newf(er.PatternError, "pattern %q needs a token before its first checksum", pattern)
+	}
+	return t, nil
+}
+
+// bracketed is a helper to parse a [...] alphabet that starts at runes[start]. It returns the position of the closing
+// bracket and the alphabet.
+func bracketed(runes []rune, start int) (int, []rune, *er.Err) {
+	alphabet := []rune{}
+	for i := start + 1; i < len(runes); i++ {
+		switch runes[i] {
+		case ']':
+			if len(alphabet) < 2 {
+				return 0, nil, er.Newf(er.AlphabetTooShortError, "alphabet %q in pattern %q must have at least length 2",
+					string(alphabet), string(runes))
+			}
+			return i, alphabet, nil
+		case '\\':
+			if i+1 < len(runes) {
+				i++
+			}
+		}
+		alphabet = append(alphabet, runes[i])
+	}
+	return 0, nil, er.Newf(er.PatternError, "pattern %q lacks a closing bracket", string(runes))
+}
+
+// index is a helper to fold the casing of a position if so requested, and to build its token index.
+func (p *position) index(ignoreCase bool) *er.Err {
+	if ignoreCase {
+		p.literal = unicode.ToUpper(p.literal)
+		for i, r := range p.alphabet {
+			p.alphabet[i] = unicode.ToUpper(r)
+		}
+	}
+	if p.alphabet == nil {
+		return nil
+	}
+	p.tokenIndex = map[rune]int{}
+	for i, r := range p.alphabet {
+		if _, ok := p.tokenIndex[r]; ok {
+			return er.Newf(er.TokenRepeatsError, "%v repeats in alphabet %q", string(r), string(p.alphabet))
+		}
+		p.tokenIndex[r] = i
+	}
+	return nil
+}
+
+// Pattern returns the pattern from which the Template was constructed.
+func (t *Template) Pattern() string {
+	return t.pattern
+}
+
+// Len returns the number of runes in a generated ID, including literals.
+func (t *Template) Len() int {
+	return len(t.positions)
+}
+
+// Tokens returns the number of non-literal runes in a generated ID, including checksums.
+func (t *Template) Tokens() int {
+	n := 0
+	for _, p := range t.positions {
+		if p.alphabet != nil {
+			n++
+		}
+	}
+	return n
+}
+
+// Checksums returns the number of checksum runes in a generated ID.
+func (t *Template) Checksums() int {
+	n := 0
+	for _, p := range t.positions {
+		if p.checksum {
+			n++
+		}
+	}
+	return n
+}
+
+// Literal returns the literal rune at position i of the pattern and true, or false when the position holds a token.
+func (t *Template) Literal(i int) (rune, bool) {
+	p := t.positions[i]
+	return p.literal, p.alphabet == nil
+}
+
+// Alphabet returns the runes that may occur at position i of the pattern: the alphabet of a token, or the literal.
+func (t *Template) Alphabet(i int) string {
+	p := t.positions[i]
+	if p.alphabet == nil {
+		return string(p.literal)
+	}
+	return string(p.alphabet)
+}
+
+// Checksum returns true when position i of the pattern holds a checksum rune.
+func (t *Template) Checksum(i int) bool {
+	return t.positions[i].checksum
+}
+
+// Accepts returns true when the rune r may occur at position i of the pattern.
+func (t *Template) Accepts(i int, r rune) bool {
+	p := t.positions[i]
+	if p.alphabet == nil {
+		return r == p.literal
+	}
+	_, ok := p.tokenIndex[r]
+	return ok
+}
+
+// Capacity returns the number of distinct values that the Template can represent, so that valid numbers run from 0
+// up to Capacity()-1. When the capacity exceeds what an uint64 can hold, zero and false are returned: then any uint64
+// can be represented.
+func (t *Template) Capacity() (uint64, bool) {
+	capacity := uint64(1)
+	for _, p := range t.positions {
+		if p.alphabet == nil || p.checksum {
+			continue
+		}
+		hi, lo := bits.Mul64(capacity, uint64(len(p.alphabet)))
+		if hi != 0 {
+			return 0, false
+		}
+		capacity = lo
+	}
+	return capacity, true
+}
+
+// ToRunes converts a uint64 to its runes representation. An error occurs when the number exceeds the capacity.
+func (t *Template) ToRunes(nr uint64) ([]rune, *er.Err) {
+	out := make([]rune, len(t.positions))
+	rest := nr
+	for i := len(t.positions) - 1; i >= 0; i-- {
+		p := t.positions[i]
+		if p.alphabet == nil || p.checksum {
+			out[i] = p.literal
+			continue
+		}
+		out[i] = p.alphabet[rest%uint64(len(p.alphabet))]
+		rest /= uint64(len(p.alphabet))
+	}
+	if rest > 0 {
+		capacity, _ := t.Capacity()
+		return nil, er.Newf(er.OutOfRangeError, "%v exceeds the capacity %v of pattern %q", nr, capacity, t.pattern)
+	}
+
+	// Fill in the checksums.
+	sum := 0
+	for i, p := range t.positions {
+		if p.alphabet == nil {
+			continue
+		}
+		if p.checksum {
+			out[i] = p.alphabet[sum%len(p.alphabet)]
+		}
+		sum += p.tokenIndex[out[i]]
+	}
+	return out, nil
+}
+
+// ToString converts a uint64 to its string representation. An error occurs when the number exceeds the capacity.
+func (t *Template) ToString(nr uint64) (string, *er.Err) {
+	runes, err := t.ToRunes(nr)
+	if err != nil {
+		return "", err
+	}
+	return string(runes), nil
+}
+
+// ToNr converts a string to its numeric representation. The literals may be omitted altogether, so that both
+// "AB-1234-C" and "AB1234C" are accepted for the pattern "AA-9999-A".
+func (t *Template) ToNr(s string) (uint64, *er.Err) {
+	if t.ignoreCase {
+		s = strings.ToUpper(s)
+	}
+	runes := []rune(s)
+	withLiterals := true
+	switch {
+	case len(runes) == t.Len():
+	case len(runes) == t.Tokens():
+		withLiterals = false
+	case len(runes) < t.Len():
+		return 0, er.Newf(er.IDTooShortError, "ID %q is shorter than pattern %q", s, t.pattern)
+	default:
+		return 0, er.Newf(er.IDTooLongError, "ID %q is longer than pattern %q", s, t.pattern)
+	}
+
+	out := uint64(0)
+	sum := 0
+	next := 0
+	for i, p := range t.positions {
+		if p.alphabet == nil {
+			if withLiterals {
+				if runes[next] != p.literal {
+					return 0, er.Newf(er.NoSuchTokenError, "token %v at position %v should be %v",
+						string(runes[next]), next+1, string(p.literal))
+				}
+				next++
+			}
+			continue
+		}
+		r := runes[next]
+		next++
+		index, ok := p.tokenIndex[r]
+		if !ok {
+			return 0, er.Newf(er.NoSuchTokenError, "token %v at position %v not in alphabet %q",
+				string(r), next, string(p.alphabet))
+		}
+		if p.checksum {
+			if want := p.alphabet[sum%len(p.alphabet)]; r != want {
+				return 0, er.Newf(er.ChecksumError, "checksum error at %v, expected %v", string(r), string(want))
+			}
+			sum += index
+			continue
+		}
+		sum += index
+		hi, lo := bits.Mul64(out, uint64(len(p.alphabet)))
+		lo, carry := bits.Add64(lo, uint64(index), 0)
+		if hi != 0 || carry != 0 {
+			return 0, er.Newf(er.OutOfRangeError, "ID %q exceeds the range of an uint64 at position %v", s, i+1)
+		}
+		out = lo
+	}
+	return out, nil
+}
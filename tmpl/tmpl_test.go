@@ -0,0 +1,226 @@
+package tmpl
+
+import (
+	"testing"
+
+	"github.com/KarelKubat/hrid/er"
+)
+
+func TestNew(t *testing.T) {
+	for _, test := range []struct {
+		pattern  string
+		wantCode er.Code
+	}{
+		{pattern: "AA-9999-A"},
+		{pattern: "[abc]9#"},
+		{pattern: "99#[01]"},
+		{pattern: `\A\9-99`},
+		{pattern: "----", wantCode: er.PatternError},
+		{pattern: "#99", wantCode: er.PatternError},
+		{pattern: "[abc", wantCode: er.PatternError},
+		{pattern: `99\`, wantCode: er.PatternError},
+		{pattern: "[a]9", wantCode: er.AlphabetTooShortError},
+		{pattern: "[aba]9", wantCode: er.TokenRepeatsError},
+	} {
+		_, err := New(test.pattern, false)
+		gotCode := er.None
+		if err != nil {
+			gotCode = err.Code
+		}
+		if gotCode != test.wantCode {
+			t.Errorf("New(%q) = _,%v, want %v", test.pattern, err, test.wantCode)
+		}
+	}
+}
+
+func TestCapacity(t *testing.T) {
+	for _, test := range []struct {
+		pattern      string
+		wantCapacity uint64
+		wantOk       bool
+	}{
+		{
+			pattern:      "99",
+			wantCapacity: 100,
+			wantOk:       true,
+		},
+		{
+			pattern:      "AA-9999-A",
+			wantCapacity: 21 * 21 * 10000 * 21,
+			wantOk:       true,
+		},
+		{
+			pattern:      "9[ab]#",
+			wantCapacity: 20,
+			wantOk:       true,
+		},
+		{
+			pattern: "XXXXXXXXXXXXXXX",
+			wantOk:  false,
+		},
+	} {
+		tp, err := New(test.pattern, false)
+		if err != nil {
+			t.Fatalf("New(%q) = _,%v, need nil error", test.pattern, err)
+		}
+		gotCapacity, gotOk := tp.Capacity()
+		if gotCapacity != test.wantCapacity || gotOk != test.wantOk {
+			t.Errorf("New(%q).Capacity() = %v,%v, want %v,%v",
+				test.pattern, gotCapacity, gotOk, test.wantCapacity, test.wantOk)
+		}
+	}
+}
+
+func TestToString(t *testing.T) {
+	for _, test := range []struct {
+		pattern    string
+		nr         uint64
+		wantString string
+		wantCode   er.Code
+	}{
+		{
+			pattern:    "AA-9999-A",
+			nr:         0,
+			wantString: "AA-0000-A",
+		},
+		{
+			pattern:    "AA-9999-A",
+			nr:         1,
+			wantString: "AA-0000-B",
+		},
+		{
+			pattern:    "AA-9999-A",
+			nr:         21,
+			wantString: "AA-0001-A",
+		},
+		{
+			pattern:    "99",
+			nr:         99,
+			wantString: "99",
+		},
+		{
+			pattern:  "99",
+			nr:       100,
+			wantCode: er.OutOfRangeError,
+		},
+		{
+			pattern:    "9[ab]#[xyz]",
+			nr:         3, // digit 1 (value 1), b (value 1), checksum 2%3 = z
+			wantString: "1bz",
+		},
+	} {
+		tp, err := New(test.pattern, false)
+		if err != nil {
+			t.Fatalf("New(%q) = _,%v, need nil error", test.pattern, err)
+		}
+		gotString, err := tp.ToString(test.nr)
+		gotCode := er.None
+		if err != nil {
+			gotCode = err.Code
+		}
+		if gotString != test.wantString || gotCode != test.wantCode {
+			t.Errorf("New(%q).ToString(%v) = %q,%v, want %q,%v",
+				test.pattern, test.nr, gotString, err, test.wantString, test.wantCode)
+		}
+	}
+}
+
+func TestToNr(t *testing.T) {
+	tp, err := New("AA-9999-#", true)
+	if err != nil {
+		t.Fatalf("New() = _,%v, need nil error", err)
+	}
+	for _, test := range []struct {
+		s        string
+		wantNr   uint64
+		wantCode er.Code
+	}{
+		{s: "AA-0000-0", wantNr: 0},
+		{s: "AB-0000-1", wantNr: 10000},
+		{s: "ab-0000-1", wantNr: 10000},
+		{s: "AB00001", wantNr: 10000},
+		{s: "AB-0000-2", wantCode: er.ChecksumError},
+		{s: "AB+0000-1", wantCode: er.NoSuchTokenError},
+		{s: "AI-0000-1", wantCode: er.NoSuchTokenError},
+		{s: "AB-000", wantCode: er.IDTooShortError},
+		{s: "AB-0000-11", wantCode: er.IDTooLongError},
+	} {
+		gotNr, err := tp.ToNr(test.s)
+		gotCode := er.None
+		if err != nil {
+			gotCode = err.Code
+		}
+		if gotNr != test.wantNr || gotCode != test.wantCode {
+			t.Errorf("ToNr(%q) = %v,%v, want %v,%v", test.s, gotNr, err, test.wantNr, test.wantCode)
+		}
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, pattern := range []string{
+		"AA-9999-A",
+		"XXXX XXXX ##",
+		"[01]-[01]-[01]-[01]",
+		"XXXXXXXXXXXXX#",
+	} {
+		tp, err := New(pattern, false)
+		if err != nil {
+			t.Fatalf("New(%q) = _,%v, need nil error", pattern, err)
+		}
+		capacity, ok := tp.Capacity()
+		for _, n := range []uint64{0, 1, 2, 12, 123456, 987654321, 1<<64 - 1} {
+			if ok && n >= capacity {
+				continue
+			}
+			s, err := tp.ToString(n)
+			if err != nil {
+				t.Fatalf("New(%q).ToString(%v) = _,%v, need nil error", pattern, n, err)
+			}
+			gotNr, err := tp.ToNr(s)
+			if err != nil {
+				t.Fatalf("New(%q).ToNr(%q) = _,%v, need nil error", pattern, s, err)
+			}
+			if gotNr != n {
+				t.Errorf("New(%q): ToString(%v) = %q, but ToNr(%q) = %v", pattern, n, s, s, gotNr)
+			}
+		}
+	}
+}
+
+func TestPositions(t *testing.T) {
+	tp, err := New("A-9", false)
+	if err != nil {
+		t.Fatalf("New() = _,%v, need nil error", err)
+	}
+	if _, ok := tp.Literal(0); ok {
+		t.Errorf("Literal(0) = _,true, want false")
+	}
+	if r, ok := tp.Literal(1); !ok || r != '-' {
+		t.Errorf("Literal(1) = %q,%v, want '-',true", r, ok)
+	}
+	if got := tp.Alphabet(1); got != "-" {
+		t.Errorf("Alphabet(1) = %q, want \"-\"", got)
+	}
+	if tp.Checksum(2) {
+		t.Errorf("Checksum(2) = true, want false")
+	}
+	if got := tp.Alphabet(2); got != Digits {
+		t.Errorf("Alphabet(2) = %q, want %q", got, Digits)
+	}
+	for _, test := range []struct {
+		i    int
+		r    rune
+		want bool
+	}{
+		{0, 'A', true},
+		{0, 'I', false},
+		{0, '1', false},
+		{1, '-', true},
+		{1, '+', false},
+		{2, '1', true},
+	} {
+		if got := tp.Accepts(test.i, test.r); got != test.want {
+			t.Errorf("Accepts(%v,%q) = %v, want %v", test.i, test.r, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,240 @@
+// Package vectors holds conformance test vectors: configurations, numbers with the IDs that they encode to, and IDs
+// with the numbers or the errors that they decode to. The vectors are the normative specification for
+// implementations of hrid in other languages. They are checked in as vectors.json, which is generated by
+// cmd/hrid-vectors and verified by the tests of this package.
+package vectors
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/KarelKubat/hrid/conv"
+	"github.com/KarelKubat/hrid/er"
+	"github.com/KarelKubat/hrid/id"
+)
+
+const (
+	// Schema is the version of the format of the vectors. It changes when the format changes, not when vectors are
+	// added.
+	Schema = 1
+	// Randoms is the number of random numbers per suite, on top of the fixed ones.
+	Randoms = 5
+)
+
+// File is the top-level structure of the vectors.
+type File struct {
+	Schema      int      `json:"schema"`
+	Description string   `json:"description"`
+	Suites      []*Suite `json:"suites"`
+}
+
+// Suite holds the vectors for one configuration. Either Conv is set, for a bare conv.Conv, or ID, for an id.ID.
+type Suite struct {
+	Name   string      `json:"name"`
+	Conv   *ConvConfig `json:"conv,omitempty"`
+	ID     *id.Opts    `json:"id,omitempty"`
+	Encode []*Encode   `json:"encode"`
+	Decode []*Decode   `json:"decode"`
+}
+
+// ConvConfig is the configuration of a conv.Conv.
+type ConvConfig struct {
+	Alphabet string `json:"alphabet"`
+	Checksum int    `json:"checksum"`
+}
+
+// Encode is a number and the ID that it encodes to, or the error. Numbers are strings, since a uint64 doesn't fit in
+// the numbers of JavaScript.
+type Encode struct {
+	Nr    string `json:"nr"`
+	ID    string `json:"id,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// Decode is an ID and the number that it decodes to, or the error. Errors are the names of er.Code.
+type Decode struct {
+	ID    string `json:"id"`
+	Nr    string `json:"nr,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// converter is what conv.Conv and id.ID have in common, for the vectors.
+type converter struct {
+	toString func(uint64) (string, *er.Err)
+	toNr     func(string) (uint64, *er.Err)
+	alphabet string
+}
+
+// suites are the configurations that the vectors cover: padding, grouping, case folding, multi-rune checksums,
+// versions and templates.
+var suites = []*Suite{
+	{Name: "conv-decimal", Conv: &ConvConfig{Alphabet: "0123456789"}},
+	{Name: "conv-checksum-2", Conv: &ConvConfig{Alphabet: "ABC", Checksum: 2}},
+	{Name: "id-defaults", ID: &id.Opts{Alphabet: id.Alphabet, StringLen: id.StringLen, IgnoreCase: id.IgnoreCase,
+		GroupSize: id.GroupSize, ChecksumLen: id.ChecksumLen}},
+	{Name: "id-hex-no-checksum", ID: &id.Opts{Alphabet: "0123456789ABCDEF", StringLen: 8, IgnoreCase: true,
+		GroupSize: 4}},
+	{Name: "id-case-sensitive", ID: &id.Opts{Alphabet: "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ",
+		ChecksumLen: 3}},
+	{Name: "id-binary-checksum-4", ID: &id.Opts{Alphabet: "01", StringLen: 16, GroupSize: 8, ChecksumLen: 4}},
+	{Name: "id-versioned", ID: &id.Opts{Alphabet: id.Alphabet, StringLen: 6, IgnoreCase: true, GroupSize: 3,
+		ChecksumLen: 1, Version: 'B'}},
+	{Name: "id-template", ID: &id.Opts{Template: "AA-9999-#", IgnoreCase: true}},
+}
+
+// Generate returns the vectors, as computed by this implementation.
+func Generate() (*File, *er.Err) {
+	f := &File{
+		Schema: Schema,
+		Description: "Conformance vectors for hrid (github.com/KarelKubat/hrid). Each suite configures a converter: " +
+			"conv (alphabet, checksum runes) or id (see hrid -config). Numbers are decimal strings. An implementation " +
+			"must encode each nr of encode to its id or fail with its error, and decode each id of decode to its nr " +
+			"or fail with its error.",
+	}
+	rnd := rand.New(rand.NewSource(1))
+	for _, s := range suites {
+		c, err := newConverter(s)
+		if err != nil {
+			return nil, err
+		}
+		out := &Suite{
+			Name: s.Name,
+			Conv: s.Conv,
+			ID:   s.ID,
+		}
+		base := uint64(utf8.RuneCountInString(c.alphabet))
+		nrs := []uint64{0, 1, base - 1, base, base*base + 1, 12, 3735928559, 9999999999999999999, math.MaxUint64}
+		for i := 0; i < Randoms; i++ {
+			nrs = append(nrs, rnd.Uint64()>>rnd.Intn(64))
+		}
+		inputs := []string{}
+		for _, n := range nrs {
+			e := &Encode{Nr: strconv.FormatUint(n, 10)}
+			str, err := c.toString(n)
+			if err != nil {
+				e.Error = err.Code.String()
+				out.Encode = append(out.Encode, e)
+				continue
+			}
+			e.ID = str
+			out.Encode = append(out.Encode, e)
+			inputs = append(inputs, variants(str, c.alphabet)...)
+		}
+		seen := map[string]bool{}
+		for _, v := range append(inputs, "", " ", "!") {
+			if !seen[v] {
+				seen[v] = true
+				out.Decode = append(out.Decode, decode(c, v))
+			}
+		}
+		f.Suites = append(f.Suites, out)
+	}
+	return f, nil
+}
+
+// Verify checks this implementation against vectors, and returns the mismatches.
+func Verify(f *File) []string {
+	failures := []string{}
+	if f.Schema != Schema {
+		return append(failures, fmt.Sprintf("schema %v, want %v", f.Schema, Schema))
+	}
+	for _, s := range f.Suites {
+		c, err := newConverter(s)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%v: %v", s.Name, err))
+			continue
+		}
+		for _, e := range s.Encode {
+			n, parseErr := strconv.ParseUint(e.Nr, 10, 64)
+			if parseErr != nil {
+				failures = append(failures, fmt.Sprintf("%v: bad nr %q", s.Name, e.Nr))
+				continue
+			}
+			got, err := c.toString(n)
+			gotErr := ""
+			if err != nil {
+				gotErr = err.Code.String()
+			}
+			if got != e.ID || gotErr != e.Error {
+				failures = append(failures, fmt.Sprintf("%v: encode %v = %q,%q, want %q,%q",
+					s.Name, e.Nr, got, gotErr, e.ID, e.Error))
+			}
+		}
+		for _, d := range s.Decode {
+			if got := decode(c, d.ID); *got != *d {
+				failures = append(failures, fmt.Sprintf("%v: decode %q = %q,%q, want %q,%q",
+					s.Name, d.ID, got.Nr, got.Error, d.Nr, d.Error))
+			}
+		}
+	}
+	return failures
+}
+
+// newConverter is a helper that instantiates the converter of a suite.
+func newConverter(s *Suite) (*converter, *er.Err) {
+	if s.Conv != nil {
+		c, err := conv.New(s.Conv.Alphabet, uint(s.Conv.Checksum))
+		if err != nil {
+			return nil, err
+		}
+		return &converter{
+			toString: func(n uint64) (string, *er.Err) { return c.ToString(n), nil },
+			toNr:     c.ToNr,
+			alphabet: s.Conv.Alphabet,
+		}, nil
+	}
+	opts := *s.ID
+	c, err := id.New(&opts)
+	if err != nil {
+		return nil, err
+	}
+	alphabet := opts.Alphabet
+	if t := c.Template(); t != nil {
+		alphabet = t.Alphabet(0)
+	}
+	return &converter{
+		toString: c.ToCheckedString,
+		toNr:     c.ToNr,
+		alphabet: alphabet,
+	}, nil
+}
+
+// decode is a helper that decodes an ID into a vector.
+func decode(c *converter, s string) *Decode {
+	d := &Decode{ID: s}
+	n, err := c.toNr(s)
+	if err != nil {
+		d.Error = err.Code.String()
+	} else {
+		d.Nr = strconv.FormatUint(n, 10)
+	}
+	return d
+}
+
+// variants is a helper that returns an ID and variations of it: other casing, other spacing, a changed last rune (a
+// checksum error, when there is a checksum), a foreign rune, and the ID without its last rune.
+func variants(s, alphabet string) []string {
+	runes := []rune(s)
+	tokens := []rune(alphabet)
+	last := runes[len(runes)-1]
+	changed := tokens[0]
+	for i, t := range tokens {
+		if t == last {
+			changed = tokens[(i+1)%len(tokens)]
+		}
+	}
+	return []string{
+		s,
+		strings.ToLower(s),
+		strings.ToUpper(s),
+		strings.ReplaceAll(s, " ", ""),
+		"  " + strings.Join(strings.Fields(s), "\t") + " ",
+		string(runes[:len(runes)-1]) + string(changed),
+		string(runes[:len(runes)/2]) + "!" + string(runes[len(runes)/2:]),
+		string(runes[:len(runes)-1]),
+	}
+}
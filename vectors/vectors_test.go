@@ -0,0 +1,51 @@
+package vectors
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"reflect"
+	"testing"
+)
+
+// TestVectors verifies the implementation against the checked-in vectors, which are the specification.
+func TestVectors(t *testing.T) {
+	data, err := os.ReadFile("vectors.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := &File{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(f); err != nil {
+		t.Fatalf("vectors.json doesn't decode: %v", err)
+	}
+	for _, failure := range Verify(f) {
+		t.Error(failure)
+	}
+
+	// The checked-in vectors must also be complete, so that changes to the suites are reviewed as vectors.
+	generated, idErr := Generate()
+	if idErr != nil {
+		t.Fatalf("Generate() returned unexpected error %v", idErr)
+	}
+	if !reflect.DeepEqual(f, generated) {
+		t.Errorf("vectors.json is stale, run: go run ./cmd/hrid-vectors -output vectors/vectors.json")
+	}
+}
+
+func TestVerify(t *testing.T) {
+	f, err := Generate()
+	if err != nil {
+		t.Fatalf("Generate() returned unexpected error %v", err)
+	}
+	f.Suites[0].Encode[0].ID = "X"
+	f.Suites[1].Decode[0].Nr = "42"
+	if got := Verify(f); len(got) != 2 {
+		t.Errorf("Verify() of tampered vectors = %q, want 2 failures", got)
+	}
+	f.Schema = Schema + 1
+	if got := Verify(f); len(got) != 1 {
+		t.Errorf("Verify() of a future schema = %q, want 1 failure", got)
+	}
+}